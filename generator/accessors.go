@@ -0,0 +1,25 @@
+package generator
+
+import "go/types"
+
+// isGetter returns true if the signature has the shape of a getter: no parameters and at least one result.
+func isGetter(sig *types.Signature) bool {
+	return sig.Params().Len() == 0 && sig.Results().Len() > 0
+}
+
+// isSetter returns true if the signature has the shape of a setter: at least one parameter, and no results or only the error result.
+func (g *generator) isSetter(sig *types.Signature) bool {
+	if sig.Params().Len() == 0 {
+		return false
+	}
+	results := sig.Results()
+	return results.Len() == 0 || (results.Len() == 1 && g.returnsError(sig))
+}
+
+// matchesAccessorFilters returns true if the signature passes the --getters and --setters filters. With both of them a method is converted if it's either a getter or a setter, and without them every method passes.
+func (g *generator) matchesAccessorFilters(sig *types.Signature) bool {
+	if !g.cfg.Getters && !g.cfg.Setters {
+		return true
+	}
+	return (g.cfg.Getters && isGetter(sig)) || (g.cfg.Setters && g.isSetter(sig))
+}
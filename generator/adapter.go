@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// adapter describes a generated adapter struct and the source interface it implements.
+type adapter struct {
+	// structName is the name of the generated adapter struct, such as MyInterfaceFuncs.
+	structName string
+	// iface is the source interface implemented by the adapter.
+	iface *types.TypeName
+	// value is set if the adapter's methods have value receivers rather than pointer receivers (see --adapter-receiver).
+	value bool
+}
+
+// adapterReceivers returns whether each of the adapters generated per interface has value receivers, following --adapter-receiver: a single adapter with pointer receivers (the default) or value receivers, or one of each.
+func (g *generator) adapterReceivers() []bool {
+	switch g.cfg.AdapterReceiver {
+	case "value":
+		return []bool{true}
+	case "both":
+		return []bool{false, true}
+	}
+	return []bool{false}
+}
+
+// adapterStructName returns the name of the adapter struct generated for the named interface, with value receivers or not.
+// With --adapter-receiver both, the adapter with value receivers is named <Interface>ValueFuncs to tell it apart from the one with pointer receivers.
+func (g *generator) adapterStructName(ifaceName string, value bool) string {
+	if value && g.cfg.AdapterReceiver == "both" {
+		return ifaceName + "ValueFuncs"
+	}
+	return ifaceName + "Funcs"
+}
+
+// adapterFieldName returns the name of the adapter struct field holding the function for the named method. The field is exported for unexported methods too, like ReadFunc for read, so it can be set outside the package.
+func adapterFieldName(methodName string) string {
+	return capitalize(methodName) + "Func"
+}
+
+// adapterFieldCollision returns why the fields of the interface's adapter can't be named after its methods, like the field FindFunc of the method Find colliding with a method FindFunc, or an empty string if they can.
+func adapterFieldCollision(iface *types.Interface) string {
+	methods := make(map[string]bool)
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods[iface.Method(i).Name()] = true
+	}
+
+	fields := make(map[string]string)
+	for i := 0; i < iface.NumMethods(); i++ {
+		name := iface.Method(i).Name()
+		field := adapterFieldName(name)
+		if methods[field] {
+			return fmt.Sprintf("the field %s of its method %s collides with its method %s", field, name, field)
+		}
+		if other, ok := fields[field]; ok {
+			return fmt.Sprintf("its methods %s and %s would both be held by the field %s", other, name, field)
+		}
+		fields[field] = name
+	}
+	return ""
+}
+
+// stringifyAdapter will take an interface and emit an adapter struct with one function type field per method, plus a method for each interface method delegating to the function in the corresponding field.
+// The adapter therefore implements the source interface, which makes it easy to stub the interface in tests: set the fields you need and pass the struct along.
+// With pointer receivers, the fields can still be changed after the adapter is passed along as the interface. With value receivers, the struct itself implements the interface.
+func (r *renderer) stringifyAdapter(structName string, obj *types.TypeName, iface *types.Interface, value bool) string {
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// %s implements %s by delegating each method to the function in the corresponding field.\n", structName, obj.Name()))
+	builder.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+	for i := 0; i < iface.NumMethods(); i++ {
+		meth := iface.Method(i)
+		builder.WriteString(fmt.Sprintf("\t%s %s\n", adapterFieldName(meth.Name()), r.funcTypeName(obj, meth.Name())))
+	}
+	builder.WriteString("}\n")
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		builder.WriteString("\n" + r.stringifyAdapterMethod(structName, iface.Method(i), value) + "\n")
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// stringifyAdapterMethod will emit the adapter method for the given interface method, which forwards its parameters to the function in the method's field and returns whatever it returns.
+func (r *renderer) stringifyAdapterMethod(structName string, meth *types.Func, value bool) string {
+	sig := meth.Type().(*types.Signature)
+
+	reserved := map[string]bool{"a": true}
+	params := r.forwardParams(sig, reserved)
+
+	var resultTypes []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		resultTypes = append(resultTypes, r.typeString(sig.Results().At(i).Type()))
+	}
+
+	call := fmt.Sprintf("a.%s(%s)", adapterFieldName(meth.Name()), params.args)
+	if len(resultTypes) > 0 {
+		call = "return " + call
+	}
+
+	receiver := "*" + structName
+	if value {
+		receiver = structName
+	}
+	return fmt.Sprintf("func (a %s) %s(%s)%s {\n\t%s\n}", receiver, meth.Name(), params.decl, resultList(resultTypes), call)
+}
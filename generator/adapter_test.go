@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateAdapter(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", EmitAdapter: true})["testdata_functypes.go"]
+
+	for _, want := range []string{
+		"// ReaderFuncs implements Reader by delegating each method to the function in the corresponding field.\ntype ReaderFuncs struct {\n\tReadFunc Read\n}\n\nfunc (a *ReaderFuncs) Read(p []byte) (int, error) {\n\treturn a.ReadFunc(p)\n}\n",
+		// Variadic parameters are forwarded as such, and parameters named like the receiver are renamed.
+		"func (a *MyInterfaceFuncs) Foo(p0 string, b int, c ...string) {\n\ta.FooFunc(p0, b, c...)\n}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+}
+
+func TestGenerateAdapterStubsInterface(t *testing.T) {
+	// The adapter stands in for MyInterface, forwarding the parameters, variadic ones included, and returning the results of its fields.
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitAdapter: true}, map[string]string{
+		"stub_test.go": `package functypes
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/eaardal/functypes/testdata"
+)
+
+func TestMyInterfaceFuncs(t *testing.T) {
+	var got []string
+	var stub testdata.MyInterface = &MyInterfaceFuncs{
+		AbcFunc: func() (string, error) { return "abc", nil },
+		BarFunc: func(a string) error { return errors.New(a) },
+		FooFunc: func(a string, b int, c ...string) { got = append([]string{a}, c...) },
+	}
+
+	if s, err := stub.Abc(); s != "abc" || err != nil {
+		t.Errorf("Abc() = %q, %v, want abc, nil", s, err)
+	}
+	if err := stub.Bar("failed"); err == nil || err.Error() != "failed" {
+		t.Errorf("Bar() error = %v, want failed", err)
+	}
+	stub.Foo("a", 1, "b", "c")
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Foo() got %q, want %q", got, want)
+	}
+}
+`,
+	})
+}
+
+func TestGenerateAdapterReceiver(t *testing.T) {
+	const (
+		pointer = "// ReaderFuncs implements Reader by delegating each method to the function in the corresponding field.\ntype ReaderFuncs struct {\n\tReadFunc Read\n}\n\nfunc (a *ReaderFuncs) Read(p []byte) (int, error) {\n\treturn a.ReadFunc(p)\n}\n"
+		value   = "// ReaderFuncs implements Reader by delegating each method to the function in the corresponding field.\ntype ReaderFuncs struct {\n\tReadFunc Read\n}\n\nfunc (a ReaderFuncs) Read(p []byte) (int, error) {\n\treturn a.ReadFunc(p)\n}\n"
+		both    = "// ReaderValueFuncs implements Reader by delegating each method to the function in the corresponding field.\ntype ReaderValueFuncs struct {\n\tReadFunc Read\n}\n\nfunc (a ReaderValueFuncs) Read(p []byte) (int, error) {\n\treturn a.ReadFunc(p)\n}\n"
+	)
+
+	tests := []struct {
+		receiver string
+		want     string
+	}{
+		{receiver: "pointer", want: pointer},
+		{receiver: "value", want: value},
+		{receiver: "both", want: pointer + "\n" + both},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.receiver, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^Reader$", EmitAdapter: true, AdapterReceiver: tt.receiver})["testdata_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\ntype Read func(p []byte) (n int, err error)\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+
+			// The generated test asserts each adapter implements Reader, with a value if its receivers are.
+			goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^Reader$", EmitAdapter: true, EmitTest: true, AdapterReceiver: tt.receiver}, nil)
+		})
+	}
+}
+
+func TestAdapterReceiverIsValidated(t *testing.T) {
+	_, err := newGenerator(Config{AdapterReceiver: "ref"})
+	if want := "--adapter-receiver must be pointer, value or both, got ref"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateSkipsAdaptersWithCollidingFields(t *testing.T) {
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: "../testdata/adapterfields", EmitAdapter: true, Validate: true})["adapterfields_functypes.go"]
+
+	if strings.Contains(got, "IndexFuncs") {
+		t.Errorf("Generate() =\n%s\nwant no IndexFuncs adapter", got)
+	}
+	want := []string{"skipping adapter for Index because the field FindFunc of its method Find collides with its method FindFunc"}
+	if got := logged(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Generate() warned %q, want %q", got, want)
+	}
+}
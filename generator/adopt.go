@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// adoption is a function type of a hand-written file given to --adopt, and the generated function types with its signature.
+type adoption struct {
+	decl    funcTypeDecl
+	matches []GeneratedDecl
+}
+
+// adopt reports which of the function types declared in the hand-written file given to --adopt could be managed by functypes instead, and under what names, to help migrating onto functypes.
+// A hand-written function type is matched to the generated function types by its signature regardless of parameter and result names, the way --compat-with matches renamed function types. Nothing is written.
+func (g *generator) adopt() error {
+	src, err := os.ReadFile(g.cfg.Adopt)
+	if err != nil {
+		return fmt.Errorf("--adopt: failed to read %s: %v", g.cfg.Adopt, err)
+	}
+	handWritten, err := parseFuncTypeDecls(g.cfg.Adopt, src)
+	if err != nil {
+		return fmt.Errorf("--adopt: %v", err)
+	}
+
+	generatedByKey := make(map[string][]GeneratedDecl)
+	for _, decl := range g.generated {
+		parsed, err := parseFuncTypeDecls(decl.Name, []byte("package p\n\n"+decl.Go))
+		if err != nil || len(parsed) != 1 {
+			logrus.Debugf("not matching %s.%s against %s because it couldn't be parsed: %v", decl.Interface, decl.Name, g.cfg.Adopt, err)
+			continue
+		}
+		generatedByKey[parsed[0].structuralKey] = append(generatedByKey[parsed[0].structuralKey], decl)
+	}
+
+	var adoptions []adoption
+	for _, decl := range handWritten {
+		// Aliases point to another type rather than declaring a signature.
+		if decl.aliasOf != "" {
+			continue
+		}
+		adoptions = append(adoptions, adoption{decl: decl, matches: generatedByKey[decl.structuralKey]})
+	}
+	sort.Slice(adoptions, func(i, j int) bool {
+		return adoptions[i].decl.name < adoptions[j].decl.name
+	})
+
+	managed := 0
+	for _, a := range adoptions {
+		logAdoption(a)
+		if len(a.matches) > 0 {
+			managed++
+		}
+	}
+	logrus.Infof("%d of the %d function types in %s can be managed by functypes", managed, len(adoptions), g.cfg.Adopt)
+	return nil
+}
+
+// logAdoption reports whether and how the hand-written function type can be managed by functypes.
+func logAdoption(a adoption) {
+	name := a.decl.name
+	if len(a.matches) == 0 {
+		logrus.Warnf("%s: no method of the scanned interfaces has its signature %s, keep it hand-written", name, a.decl.typeExpr)
+		return
+	}
+
+	var origins []string
+	for _, match := range a.matches {
+		if match.Name == name {
+			logrus.Infof("%s: matches %s.%s.%s, generated under the same name", name, match.PkgPath, match.Interface, match.Name)
+			return
+		}
+		origins = append(origins, fmt.Sprintf("%s.%s.%s (generated as %s)", match.PkgPath, match.Interface, match.Name, match.Name))
+	}
+	if len(a.matches) == 1 {
+		logrus.Infof("%s: matches %s, pass the file to --compat-with to keep %s as a deprecated alias", name, origins[0], name)
+		return
+	}
+	logrus.Infof("%s: matches several methods, pick one of %s", name, strings.Join(origins, ", "))
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestGenerateAdopt(t *testing.T) {
+	hook := test.NewLocal(logrus.StandardLogger())
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	outDir := t.TempDir()
+	if err := Generate(Config{PkgPath: "../testdata/adopt", OutDir: outDir, Adopt: "../testdata/adopt/handwritten/handwritten.go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, entry := range hook.AllEntries() {
+		if !strings.HasPrefix(entry.Message, "added: ") {
+			got = append(got, entry.Level.String()+": "+entry.Message)
+		}
+	}
+	want := []string{
+		"warning: Cancel: no method of the scanned interfaces has its signature func(id string) error, keep it hand-written",
+		"info: FindOrder: matches github.com/eaardal/functypes/testdata/adopt.OrderStore.Find (generated as Find), pass the file to --compat-with to keep FindOrder as a deprecated alias",
+		"info: Save: matches github.com/eaardal/functypes/testdata/adopt.OrderStore.Save, generated under the same name",
+		"info: 2 of the 3 function types in ../testdata/adopt/handwritten/handwritten.go can be managed by functypes",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if files := readFiles(t, outDir); len(files) != 0 {
+		t.Errorf("--adopt wrote %d files, want none", len(files))
+	}
+}
+
+func TestAdoptCantBeRendered(t *testing.T) {
+	_, err := Render(Config{PkgPath: "../testdata/adopt", Adopt: "../testdata/adopt/handwritten/handwritten.go"})
+
+	want := "--adopt reports on a hand-written file and can't be rendered"
+	if err == nil || err.Error() != want {
+		t.Errorf("Render() error = %v, want %q", err, want)
+	}
+}
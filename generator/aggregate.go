@@ -0,0 +1,30 @@
+package generator
+
+import "go/types"
+
+// isAggregate returns true if the interface only embeds other interfaces, without declaring methods of its own, like type All interface { A; B }.
+func isAggregate(iface *types.Interface) bool {
+	return iface.NumExplicitMethods() == 0 && iface.NumEmbeddeds() > 0
+}
+
+// renderedPartMethods returns the names of the aggregate's methods which come from embedded interfaces rendered to the same file, for --aggregate-mode=reference.
+// Those methods already get their function types from the embedded interfaces, so only the methods of the other embedded interfaces (like io.Closer, or an interface routed elsewhere) are rendered for the aggregate.
+func (r *renderer) renderedPartMethods(named *types.Named, iface *types.Interface) map[string]bool {
+	methods := make(map[string]bool)
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		part, ok := iface.EmbeddedType(i).(*types.Named)
+		if !ok || part.Obj().Pkg() != named.Obj().Pkg() || part.TypeArgs().Len() > 0 {
+			continue
+		}
+
+		partIface, ok := part.Underlying().(*types.Interface)
+		if !ok || r.skipReason(part.Obj().Name()) != "" {
+			continue
+		}
+
+		for j := 0; j < partIface.NumMethods(); j++ {
+			methods[partIface.Method(j).Name()] = true
+		}
+	}
+	return methods
+}
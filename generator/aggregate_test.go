@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestGenerateAggregateMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{
+			mode: "reference",
+			want: "type Get func(key string) (string, error)\ntype Put func(key string, value string) error\ntype Close func() error\n",
+		},
+		{
+			mode: "skip",
+			want: "type Get func(key string) (string, error)\ntype Put func(key string, value string) error\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/aggregate", AggregateMode: tt.mode})["aggregate_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestAggregateModeIsValidated(t *testing.T) {
+	_, err := newGenerator(Config{AggregateMode: "alias"})
+	if want := "--aggregate-mode must be expand, skip or reference, got alias"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateAggregateModeExpand(t *testing.T) {
+	// Expanding Store converts Get and Put of Getter and Putter once more, so the methods of all three interfaces are prefixed with the interface names.
+	got := generateFiles(t, Config{PkgPath: "../testdata/aggregate", AggregateMode: "expand"})["aggregate_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type GetterGet func(key string) (string, error)
+type PutterPut func(key string, value string) error
+type StoreClose func() error
+type StoreGet func(key string) (string, error)
+type StorePut func(key string, value string) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,240 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// appendFromSource renders the function type of a method whose signature references unresolved types from its source with --ast-fallback, see stringifyFromSource.
+// Only the function type is rendered: the code generated around it is rendered from the method's types, which are invalid. The method isn't counted as converted either, so the interface gets no adapter or vtable.
+func (r *renderer) appendFromSource(named *types.Named, meth *types.Func, tparams *types.TypeParamList, builder *strings.Builder) {
+	ifaceName := named.Obj().Name()
+	name := r.funcTypeName(named.Obj(), meth.Name())
+	sig := meth.Type().(*types.Signature)
+
+	var err error
+	method, referenced := r.referencedPackages(func() string {
+		var method string
+		method, err = r.stringifyFromSource(name, named, meth, r.methodTypeParams(sig, tparams))
+		return method
+	})
+	if err == nil {
+		err = checkRendered(method)
+	}
+	if err != nil {
+		r.log.Warnf("skipping method %s because its signature references types that could not be resolved, and it could not be rendered from its source: %v", meth.Name(), err)
+		return
+	}
+
+	r.log.Warnf("rendering method %s of %s from its source because its signature references types that could not be resolved, the types of %s are qualified by their names in the source and aren't checked", meth.Name(), ifaceName, name)
+	r.countImports(referenced)
+	r.decls = append(r.decls, GeneratedDecl{PkgPath: named.Obj().Pkg().Path(), Interface: ifaceName, Method: meth.Name(), Name: name, Signature: sig, Go: method})
+	builder.WriteString(method + "\n")
+	r.log.WithFields(logrus.Fields{"interface": lockKey(named), "method": meth.Name(), "functype": name}).Infof("added: %s", method)
+}
+
+// stringifyFromSource renders the function type of the method from its declaration in the source, for --ast-fallback. It's used for methods whose signature references types the type checker couldn't resolve, which can't be rendered from their types.
+// The types in the signature are qualified by what the source says: names declared in the interface's package get its qualifier, and qualified names the import of their file. An import which failed to load is referenced by its name in the file, and imported with that name as an explicit alias, since the name it declares is unknown.
+func (r *renderer) stringifyFromSource(name string, named *types.Named, meth *types.Func, typeParams typeParamLists) (string, error) {
+	if r.fset == nil || !meth.Pos().IsValid() {
+		return "", fmt.Errorf("the source of %s is unknown", meth.Name())
+	}
+	pos := r.fset.Position(meth.Pos())
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, pos.Filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %v", pos.Filename, err)
+	}
+
+	funcType := methodFuncType(fset, file, pos.Offset)
+	if funcType == nil {
+		return "", fmt.Errorf("found no declaration of %s in %s", meth.Name(), pos.Filename)
+	}
+
+	imports, standIns := fileImports(file, meth.Pkg())
+	q := &sourceQualifier{imports: r.imports, pkg: meth.Pkg(), fileImports: imports, standIns: standIns, typeParams: make(map[string]bool)}
+	for i := 0; i < named.TypeParams().Len(); i++ {
+		q.typeParams[named.TypeParams().At(i).Obj().Name()] = true
+	}
+	q.fields(funcType.Params)
+	q.fields(funcType.Results)
+	if r.cfg.NoParamNames {
+		dropNames(funcType.Params)
+		dropNames(funcType.Results)
+	}
+
+	assign := ""
+	if r.cfg.TypeAlias {
+		assign = "= "
+	}
+	// The qualified names have no position in the file, so the expression is written without regard to its layout in the source.
+	return fmt.Sprintf("type %s%s %s%s", name, typeParams.decl, assign, types.ExprString(funcType)), nil
+}
+
+// methodFuncType returns the function type of the interface method declared at the offset in the file, or nil if there's none.
+func methodFuncType(fset *token.FileSet, file *ast.File, offset int) *ast.FuncType {
+	var found *ast.FuncType
+	ast.Inspect(file, func(node ast.Node) bool {
+		field, ok := node.(*ast.Field)
+		if !ok || found != nil {
+			return found == nil
+		}
+		funcType, ok := field.Type.(*ast.FuncType)
+		if ok && len(field.Names) == 1 && fset.Position(field.Names[0].Pos()).Offset == offset {
+			found = funcType
+		}
+		return found == nil
+	})
+	return found
+}
+
+// fileImports returns the packages imported by the file by the name they're referenced by in it, and the import paths of the stand-ins among them.
+// Imports which loaded are the packages the type checker resolved. The others are stand-ins named like the file's alias of the import, or by importName if it has none.
+func fileImports(file *ast.File, pkg *types.Package) (map[string]*types.Package, map[string]bool) {
+	// The type checker stands in for the imports which failed to load with fake packages, which are never complete.
+	loaded := make(map[string]*types.Package)
+	for _, imported := range pkg.Imports() {
+		if imported.Complete() {
+			loaded[imported.Path()] = imported
+		}
+	}
+
+	imports := make(map[string]*types.Package)
+	standIns := make(map[string]bool)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imported, ok := loaded[importPath]
+		if !ok {
+			standInName := name
+			if !token.IsIdentifier(standInName) || standInName == "_" {
+				standInName = importName(importPath)
+			}
+			imported = types.NewPackage(importPath, standInName)
+			standIns[importPath] = true
+		}
+
+		if name == "" {
+			name = imported.Name()
+		}
+		imports[name] = imported
+	}
+	return imports, standIns
+}
+
+// importName guesses the name of the package with the import path from its last element, without a major version suffix like the /v2 of example.com/foo/v2 or the .v3 of gopkg.in/yaml.v3.
+func importName(importPath string) string {
+	elem := path.Base(importPath)
+	if major := majorVersion(importPath); major == elem {
+		elem = path.Base(path.Dir(importPath))
+	} else if major != "" {
+		elem = strings.TrimSuffix(elem, "."+major)
+	}
+	return strings.ReplaceAll(elem, "-", "_")
+}
+
+// dropNames removes the names of the fields, for --no-param-names. A field declaring several names, like a, b int, stands for a parameter per name, so it's repeated once per name.
+func dropNames(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	var unnamed []*ast.Field
+	for _, field := range fields.List {
+		for i := 0; i < max(len(field.Names), 1); i++ {
+			unnamed = append(unnamed, &ast.Field{Type: field.Type})
+		}
+	}
+	fields.List = unnamed
+}
+
+// sourceQualifier qualifies the type names in the source of a method declared in pkg with the names of their packages in the generated code.
+type sourceQualifier struct {
+	// imports are the imports of the generated file.
+	imports *importSet
+	// pkg is the package declaring the method.
+	pkg *types.Package
+	// fileImports are the packages imported by the file declaring the method, by name.
+	fileImports map[string]*types.Package
+	// standIns are the import paths of the fileImports which failed to load, see fileImports.
+	standIns map[string]bool
+	// typeParams are the names of the interface's type parameters, which are never qualified.
+	typeParams map[string]bool
+}
+
+// fields qualifies the types of the fields.
+func (q *sourceQualifier) fields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		field.Type = q.expr(field.Type)
+	}
+}
+
+// expr returns the type expression with its type names qualified.
+// The qualified names are written as identifiers, which types.ExprString writes as is.
+func (q *sourceQualifier) expr(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if q.typeParams[e.Name] || types.Universe.Lookup(e.Name) != nil {
+			return e
+		}
+		return ast.NewIdent(q.imports.qualifiedName(q.pkg, e.Name))
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			if imported, ok := q.fileImports[x.Name]; ok {
+				qualified := q.imports.qualifiedName(imported, e.Sel.Name)
+				if q.standIns[imported.Path()] {
+					q.imports.markExplicit(imported.Path())
+				}
+				return ast.NewIdent(qualified)
+			}
+		}
+		return e
+	case *ast.StarExpr:
+		e.X = q.expr(e.X)
+	case *ast.ParenExpr:
+		e.X = q.expr(e.X)
+	case *ast.Ellipsis:
+		e.Elt = q.expr(e.Elt)
+	case *ast.ArrayType:
+		e.Elt = q.expr(e.Elt)
+	case *ast.MapType:
+		e.Key = q.expr(e.Key)
+		e.Value = q.expr(e.Value)
+	case *ast.ChanType:
+		e.Value = q.expr(e.Value)
+	case *ast.FuncType:
+		q.fields(e.Params)
+		q.fields(e.Results)
+	case *ast.StructType:
+		q.fields(e.Fields)
+	case *ast.InterfaceType:
+		q.fields(e.Methods)
+	case *ast.IndexExpr:
+		e.X = q.expr(e.X)
+		e.Index = q.expr(e.Index)
+	case *ast.IndexListExpr:
+		e.X = q.expr(e.X)
+		for i, index := range e.Indices {
+			e.Indices[i] = q.expr(index)
+		}
+	}
+	return expr
+}
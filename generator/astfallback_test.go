@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderFromSource(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "unresolved imports are imported under the name the source uses",
+			cfg:  Config{PkgPath: "../testdata/_broken/unrelated", ASTFallback: true},
+			want: `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/_broken/unrelated"
+	missing "github.com/eaardal/functypes/testdata/does/not/exist"
+	"io"
+)
+
+type Get func(key string, opts ...missing.Option) (*missing.Thing, map[string]unrelated.Value, error)
+type Put func(key string, value unrelated.Value, w io.Writer) error
+`,
+		},
+		{
+			name: "without names",
+			cfg:  Config{PkgPath: "../testdata/_broken/unrelated", ASTFallback: true, NoParamNames: true},
+			want: `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/_broken/unrelated"
+	missing "github.com/eaardal/functypes/testdata/does/not/exist"
+	"io"
+)
+
+type Get func(string, ...missing.Option) (*missing.Thing, map[string]unrelated.Value, error)
+type Put func(string, unrelated.Value, io.Writer) error
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := t.TempDir()
+			tt.cfg.OutDir = outDir
+
+			files, err := Render(tt.cfg)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := string(files[filepath.Join(outDir, "unrelated_functypes.go")]); got != tt.want {
+				t.Errorf("Render() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// checkPackageErrors fails if any of the packages reported errors while loading, such as dependencies that couldn't be found.
+// With --best-effort the errors are logged as warnings instead, and processing continues with whatever could be resolved (methods referencing unresolved types are skipped, see hasInvalidType).
+func checkPackageErrors(pkgs []*packages.Package, bestEffort bool) error {
+	var messages []string
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			messages = append(messages, pkgErr.Error())
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if !bestEffort {
+		return fmt.Errorf("failed to load packages (use --best-effort to generate what can be resolved):\n%s", strings.Join(messages, "\n"))
+	}
+
+	for _, message := range messages {
+		logrus.Warnf("ignoring package error: %s", message)
+	}
+	return nil
+}
+
+// hasInvalidType returns true if the type, or any type it's composed of, could not be resolved by the type checker.
+// The type checker marks unresolved types (e.g. from a missing dependency) as types.Typ[types.Invalid], which would otherwise be rendered as "invalid type" in the generated code.
+func hasInvalidType(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Kind() == types.Invalid
+	case *types.Pointer:
+		return hasInvalidType(t.Elem())
+	case *types.Slice:
+		return hasInvalidType(t.Elem())
+	case *types.Array:
+		return hasInvalidType(t.Elem())
+	case *types.Chan:
+		return hasInvalidType(t.Elem())
+	case *types.Map:
+		return hasInvalidType(t.Key()) || hasInvalidType(t.Elem())
+	case *types.Signature:
+		return hasInvalidTupleType(t.Params()) || hasInvalidTupleType(t.Results())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if hasInvalidType(t.Field(i).Type()) {
+				return true
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			if hasInvalidType(t.Method(i).Type()) {
+				return true
+			}
+		}
+	case *types.Named:
+		// The named type itself has been resolved, so only its type arguments are left to check.
+		typeArgs := t.TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			if hasInvalidType(typeArgs.At(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasInvalidTupleType returns true if any of the variables in the tuple has an unresolved type.
+func hasInvalidTupleType(tuple *types.Tuple) bool {
+	for i := 0; i < tuple.Len(); i++ {
+		if hasInvalidType(tuple.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
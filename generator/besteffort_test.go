@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPackageErrors(t *testing.T) {
+	g, err := newGenerator(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := g.loadPackages("../testdata/_broken/partial")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = checkPackageErrors(pkgs, false)
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to load packages (use --best-effort to generate what can be resolved):\n") {
+		t.Errorf("checkPackageErrors() error = %v, want the load errors", err)
+	}
+
+	if err := checkPackageErrors(pkgs, true); err != nil {
+		t.Errorf("checkPackageErrors() with best effort error = %v, want nil", err)
+	}
+}
+
+func TestGenerateBestEffort(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/_broken/partial", BestEffort: true})["partial_functypes.go"]
+
+	// Fetch references a type of the missing package and is skipped, while the resolvable methods are kept.
+	want := generatedHeader + "\n\npackage functypes\n\ntype Name func() string\ntype Ping func() error\n"
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
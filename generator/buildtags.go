@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"go/build/constraint"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// localBuildConstraint returns the build constraint the files generated into the --qualify-relative-to package inherit, so they compile in the same configurations as the package's own files. That's its dominant constraint: the one declared by most of its .go files.
+// It returns an empty string if most of the files have no constraint, in which case the generated files don't get one either.
+func (g *generator) localBuildConstraint(pkgs []*packages.Package) string {
+	var files []string
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == g.localPkg.Path() {
+			files = pkg.GoFiles
+		}
+	}
+	if files == nil {
+		loaded, err := packagesLoad(&packages.Config{Mode: packages.NeedName | packages.NeedFiles}, g.localPkg.Path())
+		if err != nil || len(loaded) == 0 {
+			logrus.Debugf("not inheriting the build constraint of %s because its files could not be listed: %v", g.localPkg.Path(), err)
+			return ""
+		}
+		files = loaded[0].GoFiles
+	}
+
+	counts := make(map[string]int)
+	for _, file := range files {
+		counts[fileBuildConstraint(file)]++
+	}
+
+	constraints := make([]string, 0, len(counts))
+	for expr := range counts {
+		constraints = append(constraints, expr)
+	}
+	// The most common constraint wins, and ties go to the one sorting first so the choice is the same across runs.
+	sort.Slice(constraints, func(i, j int) bool {
+		if counts[constraints[i]] != counts[constraints[j]] {
+			return counts[constraints[i]] > counts[constraints[j]]
+		}
+		return constraints[i] < constraints[j]
+	})
+
+	dominant := constraints[0]
+	if dominant == "" {
+		return ""
+	}
+	if counts[dominant] < len(files) {
+		logrus.Warnf("the generated files inherit the build constraint %q of %d of the %d files of %s, the others declare different ones", dominant, counts[dominant], len(files), g.localPkg.Path())
+	} else {
+		logrus.Infof("the generated files inherit the build constraint %q of %s", dominant, g.localPkg.Path())
+	}
+	return dominant
+}
+
+// fileBuildConstraint returns the //go:build constraint of the .go file, or an empty string if it has none or can't be read.
+func fileBuildConstraint(filePath string) string {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debugf("failed to read the build constraint of %s: %v", filepath.Base(filePath), err)
+		return ""
+	}
+
+	// Build constraints must appear before the package clause.
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if !constraint.IsGoBuild(line) {
+			continue
+		}
+		if expr, err := constraint.Parse(line); err == nil {
+			return expr.String()
+		}
+	}
+	return ""
+}
+
+// withBuildConstraint adds the //go:build line of the constraint to the generated file, right after the generatedHeader. It returns the content as is if the constraint is empty.
+func withBuildConstraint(content, expr string) string {
+	if expr == "" {
+		return content
+	}
+	return strings.Replace(content, generatedHeader+"\n", generatedHeader+"\n\n//go:build "+expr+"\n", 1)
+}
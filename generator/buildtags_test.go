@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateInheritsBuildConstraint(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/tagged", QualifyRelativeTo: "github.com/eaardal/functypes/testdata/tagged"})["tagged_functypes.go"]
+
+	want := generatedHeader + `
+
+//go:build unix
+
+package tagged
+
+type Notify func(sig Signal) error
+type Watch func(path string) (<-chan Signal, error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateTestFileInheritsBuildConstraint(t *testing.T) {
+	files := generateFiles(t, Config{PkgPath: "../testdata/tagged", QualifyRelativeTo: "github.com/eaardal/functypes/testdata/tagged", EmitTest: true})
+
+	for _, name := range []string{"tagged_functypes.go", "tagged_functypes_test.go"} {
+		if want := generatedHeader + "\n\n//go:build unix\n\npackage tagged\n"; !strings.HasPrefix(files[name], want) {
+			t.Errorf("%s doesn't start with\n%s\ngot\n%s", name, want, files[name])
+		}
+	}
+}
+
+func TestGenerateWarnsAboutMixedBuildConstraints(t *testing.T) {
+	dir, err := os.MkdirTemp("../testdata", "mixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	name := filepath.Base(dir)
+	sources := map[string]string{
+		"a.go": "//go:build linux\n\npackage " + name + "\n\ntype A interface{ A() }\n",
+		"b.go": "//go:build linux\n\npackage " + name + "\n\ntype B interface{ B() }\n",
+		"c.go": "package " + name + "\n\ntype C interface{ C() }\n",
+	}
+	for file, source := range sources {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: dir, QualifyRelativeTo: "github.com/eaardal/functypes/testdata/" + name})[name+"_functypes.go"]
+
+	// The methods are named like their interfaces, so they're prefixed with the interface name in the package.
+	want := generatedHeader + "\n\n//go:build linux\n\npackage " + name + "\n\ntype AA func()\ntype BB func()\ntype CC func()\n"
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantWarnings := []string{`the generated files inherit the build constraint "linux" of 2 of the 3 files of github.com/eaardal/functypes/testdata/` + name + `, the others declare different ones`}
+	if got := logged(); !slices.Equal(got, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", got, wantWarnings)
+	}
+}
+
+func TestWithBuildConstraint(t *testing.T) {
+	content := generatedHeader + "\n\npackage x\n"
+
+	if got := withBuildConstraint(content, ""); got != content {
+		t.Errorf("withBuildConstraint() without a constraint =\n%s\nwant\n%s", got, content)
+	}
+
+	want := generatedHeader + "\n\n//go:build linux && amd64\n\npackage x\n"
+	if got := withBuildConstraint(content, "linux && amd64"); got != want {
+		t.Errorf("withBuildConstraint() =\n%s\nwant\n%s", got, want)
+	}
+}
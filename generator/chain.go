@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// stringifyChain will take an interface method and emit a <Method>Middleware type decorating its function type, plus a Chain<Method> combinator composing middlewares.
+// The middlewares are applied in the order they're given, so the first one is the outermost: ChainHandle(a, b)(h) is a(b(h)), and a sees each call first.
+func (r *renderer) stringifyChain(meth *types.Func, typeParams typeParamLists) string {
+	name := meth.Name()
+	funcType := name + typeParams.use
+	middleware := name + "Middleware" + typeParams.use
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// %sMiddleware decorates a %s, for example to add logging or retries around it.\n", name, name))
+	builder.WriteString(fmt.Sprintf("type %sMiddleware%s func(%s) %s\n\n", name, typeParams.decl, funcType, funcType))
+	builder.WriteString(fmt.Sprintf("// Chain%s composes the middlewares into one, applying them in the given order: the first middleware is the outermost.\n", name))
+	builder.WriteString(fmt.Sprintf("func Chain%s%s(ms ...%s) %s {\n", name, typeParams.decl, middleware, middleware))
+	builder.WriteString(fmt.Sprintf("\treturn func(next %s) %s {\n", funcType, funcType))
+	builder.WriteString("\t\tfor i := len(ms) - 1; i >= 0; i-- {\n\t\t\tnext = ms[i](next)\n\t\t}\n\t\treturn next\n\t}\n}")
+	return builder.String()
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateChain(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/middleware", EmitChain: true})["middleware_functypes.go"]
+
+	want := `type Handle func(path string) (status int, err error)
+
+// HandleMiddleware decorates a Handle, for example to add logging or retries around it.
+type HandleMiddleware func(Handle) Handle
+
+// ChainHandle composes the middlewares into one, applying them in the given order: the first middleware is the outermost.
+func ChainHandle(ms ...HandleMiddleware) HandleMiddleware {
+`
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+}
+
+func TestGenerateChainComposesInOrder(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata/middleware", EmitChain: true}, map[string]string{
+		"chain_test.go": `package functypes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string) HandleMiddleware {
+		return func(next Handle) Handle {
+			return func(path string) (int, error) {
+				calls = append(calls, name)
+				return next(path)
+			}
+		}
+	}
+	handle := func(path string) (int, error) {
+		calls = append(calls, "handle "+path)
+		return 200, nil
+	}
+
+	status, err := ChainHandle(record("a"), record("b"), record("c"))(handle)("/x")
+	if status != 200 || err != nil {
+		t.Errorf("handle() = %d, %v, want 200, nil", status, err)
+	}
+	if want := []string{"a", "b", "c", "handle /x"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+`,
+	})
+}
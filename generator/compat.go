@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// funcTypeDecl is a type declaration found in a generated file.
+type funcTypeDecl struct {
+	// name is the name of the declared type.
+	name string
+	// typeExpr is the source code of the declared type, such as "func(a string) error".
+	typeExpr string
+	// structuralKey identifies the signature regardless of parameter and result names, so that "func(a string) error" and "func(b string) error" are considered the same.
+	structuralKey string
+	// aliasOf is the name of the aliased type if the declaration is an alias like "type Foo = Bar".
+	aliasOf string
+}
+
+// parseFuncTypeDecls parses the given Go source and returns the function type declarations and type aliases declared in it, in source order. See parseTopLevelDecls.
+func parseFuncTypeDecls(fileName string, src []byte) ([]funcTypeDecl, error) {
+	fset, parsed, err := parseTopLevelDecls(fileName, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", fileName, err)
+	}
+
+	var decls []funcTypeDecl
+	for _, decl := range parsed {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		typeSpec := genDecl.Specs[0].(*ast.TypeSpec)
+
+		if ident, ok := typeSpec.Type.(*ast.Ident); ok && typeSpec.Assign.IsValid() {
+			decls = append(decls, funcTypeDecl{name: typeSpec.Name.Name, typeExpr: ident.Name, aliasOf: ident.Name})
+			continue
+		}
+
+		funcType, ok := typeSpec.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+
+		decls = append(decls, funcTypeDecl{
+			name:          typeSpec.Name.Name,
+			typeExpr:      printNode(fset, funcType),
+			structuralKey: structuralKey(fset, funcType),
+		})
+	}
+	return decls, nil
+}
+
+// structuralKey returns the function type with all parameter and result names stripped, i.e. "func(a, b string) (n int, err error)" becomes "func(string, string) (int, error)".
+func structuralKey(fset *token.FileSet, funcType *ast.FuncType) string {
+	key := "func(" + strings.Join(fieldTypes(fset, funcType.Params), ", ") + ")"
+	if results := fieldTypes(fset, funcType.Results); len(results) > 0 {
+		key += " (" + strings.Join(results, ", ") + ")"
+	}
+	return key
+}
+
+// fieldTypes returns the source code of the type of each entry in the field list, repeated once per name for fields declaring several names at once.
+func fieldTypes(fset *token.FileSet, fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+
+	var typeExprs []string
+	for _, field := range fields.List {
+		typ := printNode(fset, field.Type)
+
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			typeExprs = append(typeExprs, typ)
+		}
+	}
+	return typeExprs
+}
+
+// compatShims compares the function types of a previously generated file (oldSrc) with the newly generated content (newSrc) and returns deprecated declarations for every old function type that no longer exists, so that downstream code keeps compiling while it migrates.
+// An old function type that has been renamed (i.e. exactly one new function type has the same structural signature) becomes an alias to the new name. An old function type that has been removed keeps its old definition.
+// Aliases emitted by a previous run are carried over as long as the type they point to still exists.
+func compatShims(oldFileName string, oldSrc, newSrc []byte) (string, error) {
+	oldDecls, err := parseFuncTypeDecls(oldFileName, oldSrc)
+	if err != nil {
+		return "", err
+	}
+
+	newDecls, err := parseFuncTypeDecls("new output", newSrc)
+	if err != nil {
+		return "", err
+	}
+
+	oldByName := make(map[string]funcTypeDecl)
+	for _, decl := range oldDecls {
+		oldByName[decl.name] = decl
+	}
+	newNames := make(map[string]bool)
+	newNamesByKey := make(map[string][]string)
+	for _, decl := range newDecls {
+		newNames[decl.name] = true
+		newNamesByKey[decl.structuralKey] = append(newNamesByKey[decl.structuralKey], decl.name)
+	}
+
+	// The old function types which no longer exist are the removed declarations, which Diff returns sorted by name.
+	var removed []funcTypeDecl
+	for _, change := range Diff(map[string][]byte{oldFileName: oldSrc}, map[string][]byte{oldFileName: newSrc}) {
+		if old, ok := oldByName[change.Name]; ok && change.Kind == Removed {
+			removed = append(removed, old)
+		}
+	}
+
+	builder := &strings.Builder{}
+	for _, old := range removed {
+		if old.aliasOf != "" {
+			continue
+		}
+		newNames[old.name] = true
+
+		candidates := newNamesByKey[old.structuralKey]
+		if len(candidates) == 1 {
+			builder.WriteString(fmt.Sprintf("// Deprecated: Use %s instead.\ntype %s = %s\n", candidates[0], old.name, candidates[0]))
+			logrus.Infof("added compatibility alias: %s = %s", old.name, candidates[0])
+			continue
+		}
+
+		if len(candidates) > 1 {
+			logrus.Warnf("%s matches several new function types (%s), keeping its old definition", old.name, strings.Join(candidates, ", "))
+		}
+		builder.WriteString(fmt.Sprintf("// Deprecated: %s no longer exists in the source interface and will be removed.\ntype %s %s\n", old.name, old.name, old.typeExpr))
+		logrus.Infof("added compatibility type: %s", old.name)
+	}
+
+	// Aliases are handled last so they can also point to the compatibility declarations emitted above.
+	for _, old := range removed {
+		if old.aliasOf == "" || !newNames[old.aliasOf] {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("// Deprecated: Use %s instead.\ntype %s = %s\n", old.aliasOf, old.name, old.aliasOf))
+	}
+	return builder.String(), nil
+}
+
+// readCompatShims reads the previously generated file given in --compat-with and returns the compatibility shims to append to the new content.
+func readCompatShims(oldFilePath string, newSrc []byte) (string, error) {
+	oldSrc, err := os.ReadFile(oldFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", oldFilePath, err)
+	}
+	return compatShims(oldFilePath, oldSrc, newSrc)
+}
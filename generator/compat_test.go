@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCompatWith(t *testing.T) {
+	// The previous generation had Read under another name with other parameter names, and a method which has been removed since.
+	oldFilePath := filepath.Join(t.TempDir(), "testdata_functypes.go")
+	oldSrc := "package functypes\n\ntype ReadBytes func(b []byte) (int, error)\ntype Gone func(x int)\ntype Aaa func()\n"
+	if err := os.WriteFile(oldFilePath, []byte(oldSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generateFiles(t, Config{PkgPath: "../testdata", CompatWith: oldFilePath})["testdata_functypes.go"]
+
+	want := "type Read func(p []byte) (n int, err error)\n\n" +
+		"// Deprecated: Gone no longer exists in the source interface and will be removed.\ntype Gone func(x int)\n\n" +
+		"// Deprecated: Use Read instead.\ntype ReadBytes = Read\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to end with\n%s", got, want)
+	}
+	// Function types which still exist don't get a shim.
+	if strings.Contains(got, "type Aaa =") {
+		t.Errorf("Generate() =\n%s\nwant no shim for Aaa", got)
+	}
+}
+
+func TestCompatShims(t *testing.T) {
+	const header = "package functypes\n\n"
+
+	tests := []struct {
+		name   string
+		oldSrc string
+		newSrc string
+		want   string
+	}{
+		{
+			name:   "unchanged function types get no shims",
+			oldSrc: header + "type Fetch func(id string) error\n",
+			newSrc: header + "type Fetch func(id string) error\n",
+		},
+		{
+			name:   "renamed function types become aliases",
+			oldSrc: header + "type Fetch func(id string) error\n",
+			newSrc: header + "type Get func(key string) error\n",
+			want:   "// Deprecated: Use Get instead.\ntype Fetch = Get\n",
+		},
+		{
+			name:   "removed function types keep their definition",
+			oldSrc: header + "type Gone func(n int) bool\n",
+			newSrc: header + "type Get func(key string) error\n",
+			want:   "// Deprecated: Gone no longer exists in the source interface and will be removed.\ntype Gone func(n int) bool\n",
+		},
+		{
+			name:   "function types matching several new ones keep their definition",
+			oldSrc: header + "type Fetch func(id string) error\n",
+			newSrc: header + "type Get func(key string) error\ntype Delete func(key string) error\n",
+			want:   "// Deprecated: Fetch no longer exists in the source interface and will be removed.\ntype Fetch func(id string) error\n",
+		},
+		{
+			name:   "aliases of a previous run are carried over",
+			oldSrc: header + "type Fetch func(id string) error\n\n// Deprecated: Use Fetch instead.\ntype Load = Fetch\n",
+			newSrc: header + "type Get func(key string) error\n",
+			want:   "// Deprecated: Use Get instead.\ntype Fetch = Get\n// Deprecated: Use Fetch instead.\ntype Load = Fetch\n",
+		},
+		{
+			name:   "aliases of types that no longer exist are dropped",
+			oldSrc: header + "type Fetch func(id string) error\n\ntype Load = Other\n",
+			newSrc: header + "type Fetch func(id string) error\n",
+		},
+		{
+			name:   "names declared as something else now get no shim",
+			oldSrc: header + "type Fetch func(id string) error\n",
+			newSrc: header + "type Fetch struct{}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compatShims("old.go", []byte(tt.oldSrc), []byte(tt.newSrc))
+			if err != nil {
+				t.Fatalf("compatShims() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("compatShims() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package generator
+
+import "go/types"
+
+// Config holds the options for a single Generate run. Each field corresponds to a command line flag of the functypes app, see the flag descriptions in main.go for details. MethodFilter is the exception, it's only available to library users.
+// The zero value of a field means the flag's default.
+type Config struct {
+	// PkgPath is the path to a Go package directory, or a package pattern like ./... Defaults to the current directory, which is the package's directory when run by go generate.
+	PkgPath string
+	// OutDir is the directory where the generated files are written. Defaults to functypes.
+	OutDir string
+	// Route is a semicolon separated list of <pattern>=<dir> entries sending the interfaces with a name matching the glob pattern to another output directory than OutDir.
+	Route string
+	// FromGoList scans the packages listed by the output of go list -json read from stdin, instead of PkgPath.
+	FromGoList bool
+	// Jobs is how many of the packages matched by a package pattern or listed by FromGoList are rendered at a time. Packages sharing an output file are rendered together. Defaults to 1.
+	Jobs int
+	// ExportFile is a compiled export data file to load the package from instead of from source, with PkgPath as the package's import path.
+	ExportFile string
+	// Overlay is a JSON file replacing the contents of source files while loading the packages, in the format of the -overlay flag of go build.
+	Overlay string
+	// SeedFile is the .go file in PkgPath used to load the package, instead of the first .go file in the directory.
+	SeedFile string
+	// File limits the conversion to interfaces declared in this file.
+	File string
+
+	// Include and Exclude are regular expressions selecting which interfaces to convert.
+	Include, Exclude string
+	// IncludeMethods and ExcludeMethods are regular expressions selecting which methods to convert.
+	IncludeMethods, ExcludeMethods string
+	// MethodFilter selects which methods to convert by their interface, name and signature, on top of IncludeMethods and ExcludeMethods. A method is skipped if it returns false.
+	MethodFilter func(iface, method string, sig *types.Signature) bool
+	// Method only converts the methods with this name, across all interfaces. Interfaces declaring it with the same signature share one function type, and differing signatures fail the run.
+	Method string
+	// Getters only converts methods shaped like getters (no parameters, at least one result), and Setters only methods shaped like setters (at least one parameter, no results or only an error). With both, methods of either shape are converted.
+	Getters, Setters bool
+	// FromType is a struct type, by name or in the form <import path>.<TypeName>, whose interface typed fields select the interfaces to convert, instead of the interfaces declared in PkgPath.
+	FromType string
+	// FromTypeDepth is how many levels of struct fields FromType's dependencies are looked for in: 1 for its own fields, 2 to include the fields of the structs it holds, and so on. Defaults to 3.
+	FromTypeDepth int
+	// OnlyImplemented only converts interfaces which at least one of the scanned packages' types implements, pruning dead abstractions.
+	OnlyImplemented bool
+	// CaseInsensitive makes the include and exclude expressions match case-insensitively.
+	CaseInsensitive bool
+	// FailOnUnexportedMethods fails when an interface has unexported methods, while SkipUnexportedMethods skips them without a warning. By default they're skipped with a warning.
+	FailOnUnexportedMethods, SkipUnexportedMethods bool
+	// ExportUnexported converts unexported methods too, to function types with the method name capitalized, like Read for read.
+	ExportUnexported bool
+
+	// Emitter is the name of the emitter writing the generated files: go (the built-in Go code) or a custom Emitter registered with RegisterEmitter. Defaults to go.
+	Emitter string
+
+	// EmitResultStructs also emits a <Name>Result struct for methods returning more than one value.
+	EmitResultStructs bool
+	// EmitAdapter also emits a <Interface>Funcs struct per interface implementing the interface through function type fields.
+	EmitAdapter bool
+	// AdapterReceiver is whether the adapter methods have pointer or value receivers: pointer, value or both (an adapter of each, the one with value receivers named <Interface>ValueFuncs). Defaults to pointer.
+	AdapterReceiver string
+	// EmitVTable also emits a <Interface>VTable struct per interface with a function type field per method, and a Populate method filling it from an implementation of the interface.
+	EmitVTable bool
+	// EmitTest also writes a <pkg>_functypes_test.go asserting that the adapters implement their source interfaces. Implies EmitAdapter.
+	EmitTest bool
+	// EmitExamples also writes a <pkg>_functypes_test.go with an Example<Name> function per function type, as a starting point for documenting them.
+	EmitExamples bool
+	// EmitNilChecks also emits an IsSet method per function type, reporting whether it's not nil.
+	EmitNilChecks bool
+	// EmitMust also emits a Must<Name> wrapper for function types returning an error.
+	EmitMust bool
+	// EmitErrorVars also emits a sentinel Err<Interface><Method> var per method returning an error, for the consumer to assign the errors to return or expect.
+	EmitErrorVars bool
+	// EmitCtxGuard also emits a Guard<Name> wrapper for function types taking a context.Context first and returning an error, which returns ctx.Err() instead of calling the function once the context is done.
+	EmitCtxGuard bool
+	// EmitChain also emits a <Name>Middleware type and a Chain<Name> combinator per function type.
+	EmitChain bool
+	// EmitZeroArgs also emits a Zero<Name>Args function per function type, returning the zero value of each parameter.
+	EmitZeroArgs bool
+	// EmitInit also emits an init function registering each function type by name with RegisterFunc.
+	EmitInit bool
+	// RegisterFunc is the func(string, any) function EmitInit registers with, in the form <import path>.<FuncName>.
+	RegisterFunc string
+	// EmitStubs also emits a Stub<Name> function per function type, returning an implementation which returns zero values.
+	EmitStubs bool
+	// StubError is what stubs return as the error: nil or sentinel (the generated ErrNotImplemented). Defaults to nil.
+	StubError string
+	// ErrorType is the type treated as the error result, in the form <import path>.<TypeName>. Defaults to the builtin error.
+	ErrorType string
+
+	// Provenance adds a comment to each function type naming the interface and package it was generated from.
+	Provenance bool
+	// ProvenanceSource is which interface inherited methods are attributed to with Provenance: embedder or definer. Defaults to embedder.
+	ProvenanceSource string
+
+	// ReplaceType is a comma separated list of <old type>=<new type> entries, rendering the old type as the new one in the generated code. Types are in the form [*]<import path>.<TypeName>.
+	ReplaceType string
+
+	// InlinePackage is a comma separated list of import paths of packages whose types referenced by the function types are copied into the generated files, instead of imported.
+	InlinePackage string
+
+	// NoParamNames drops the parameter and result names from the rendered signatures, so renaming a parameter doesn't change the generated code.
+	NoParamNames bool
+
+	// TypeAlias renders the function types as aliases of their function signatures, like type Read = func(p []byte) (n int, err error), instead of as defined types.
+	TypeAlias bool
+
+	// Subpackages writes the function types of each interface into their own subpackage of OutDir, named after the interface in lower case, and an aggregator in OutDir re-exporting their types with aliases.
+	Subpackages bool
+
+	// NameTemplate is a text/template rendering the name of each function type from the names of its .Interface, .Method and the interface's .Package, like {{.Interface}}{{.Method}} for ReaderRead. The first letter is capitalized. The names aren't prefixed when they collide with each other, like the default names are.
+	NameTemplate string
+
+	// FlattenUnique only prefixes the function types of the methods converted for several interfaces with the interface name, like ReadCloserClose, while the other methods of those interfaces keep their name, like Read. By default, all methods of the interfaces with such a method get the prefix.
+	FlattenUnique bool
+
+	// MethodExpressions renders the function types with the interface as their first parameter, like the method expression Reader.Read, so method expressions can be assigned to them.
+	MethodExpressions bool
+
+	// QualifyRelativeTo is the import path of a package to render the generated files as part of: its types aren't qualified or imported, and the files declare its package name.
+	QualifyRelativeTo string
+	// SamePackage renders the generated file as part of the scanned package, like QualifyRelativeTo with the package's import path. OutDir defaults to PkgPath with it.
+	SamePackage bool
+
+	// ExpandAliases renders type aliases as the types they denote, instead of by the alias name.
+	ExpandAliases bool
+
+	// AggregateMode is how interfaces which only embed other interfaces are rendered: expand, skip or reference. Defaults to expand.
+	AggregateMode string
+
+	// WidenParams replaces struct typed parameters in function types with the narrowest interface from the scanned package they implement.
+	WidenParams bool
+
+	// ImportsReport is a file to write the packages referenced by the generated function types to, with the number of function types referencing each.
+	ImportsReport string
+
+	// EmitSummary is a Markdown file to write a table of the interfaces, their methods and the generated function types to, for documentation and review.
+	EmitSummary string
+
+	// Explain is the name of an interface to log the rendering decisions of, method by method.
+	Explain string
+
+	// Validate type checks the generated files before they're written, failing on the first type error.
+	Validate bool
+
+	// MaxInterfaces fails the run when more interfaces than this are processed, as a safety valve against scanning a huge tree by accident. Zero means no limit.
+	MaxInterfaces int
+
+	// BestEffort generates what can be resolved when packages fail to load, instead of failing.
+	BestEffort bool
+	// ASTFallback renders the methods whose signatures reference unresolved types from their source instead of skipping them. Implies BestEffort.
+	ASTFallback bool
+
+	// Force overwrites existing output files even if they were not generated by functypes.
+	Force bool
+	// ForceGlob and ProtectGlob are comma separated glob patterns scoping the overwrite protection to specific output paths.
+	ForceGlob, ProtectGlob string
+
+	// EmptyInterface is how the empty interface is written: any or interface{}. Defaults to any.
+	EmptyInterface string
+
+	// Lock is a file recording the generated interfaces and the hashes of their signatures, written after generating.
+	Lock string
+	// KeepAliases records the method signatures of the generated interfaces in the Lock file, and emits a deprecated alias from the old function type name to the new one for each method renamed since the previous run. Requires Lock.
+	KeepAliases bool
+	// Frozen fails when an interface is generated that isn't listed in the Lock file, instead of adding it.
+	Frozen bool
+
+	// ImportsOnly makes Render return the import block each generated file would have instead of the file, for splicing into hand-written files. Generate fails with it.
+	ImportsOnly bool
+
+	// Adopt is a hand-written file of function types to match against the generated function types by signature, reporting which of them functypes can manage instead of writing anything.
+	Adopt string
+
+	// Clean deletes the files listed in the manifest in OutDir instead of generating, see the --clean flag.
+	Clean bool
+
+	// Update is a comma separated list of interfaces to regenerate within the existing output files, keeping the declarations of all other interfaces as they are.
+	Update string
+
+	// CompatWith is a previously generated file; function types renamed or removed since then are kept as deprecated declarations.
+	CompatWith string
+}
+
+// withDefaults returns a copy of the config with the defaults applied to unset fields.
+func (cfg Config) withDefaults() Config {
+	if cfg.PkgPath == "" {
+		cfg.PkgPath = defaultPkgPath()
+	}
+	if cfg.OutDir == "" && cfg.SamePackage {
+		cfg.OutDir = cfg.PkgPath
+	}
+	if cfg.OutDir == "" {
+		cfg.OutDir = "functypes"
+	}
+	if cfg.Emitter == "" {
+		cfg.Emitter = goEmitterName
+	}
+	if cfg.EmptyInterface == "" {
+		cfg.EmptyInterface = "any"
+	}
+	if cfg.ProvenanceSource == "" {
+		cfg.ProvenanceSource = "embedder"
+	}
+	if cfg.AdapterReceiver == "" {
+		cfg.AdapterReceiver = "pointer"
+	}
+	if cfg.FromTypeDepth == 0 {
+		cfg.FromTypeDepth = 3
+	}
+	if cfg.Jobs == 0 {
+		cfg.Jobs = 1
+	}
+	if cfg.AggregateMode == "" {
+		cfg.AggregateMode = "expand"
+	}
+	if cfg.StubError == "" {
+		cfg.StubError = "nil"
+	}
+	if cfg.EmitTest {
+		cfg.EmitAdapter = true
+	}
+	if cfg.ASTFallback {
+		cfg.BestEffort = true
+	}
+	return cfg
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// stringifyCtxGuard will take an interface method taking a context.Context as its first parameter and returning an error, and emit a Guard<Method> function wrapping the function type so that it returns ctx.Err() without calling the function once the context is done.
+// Returns an empty string for other methods. Only the builtin error result gets the context's error, since an --error-type can't hold it.
+func (r *renderer) stringifyCtxGuard(meth *types.Func, typeParams typeParamLists) string {
+	sig, ok := meth.Type().Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() == 0 || !isContext(sig.Params().At(0).Type()) {
+		return ""
+	}
+	results := sig.Results()
+	if results.Len() == 0 || !types.Identical(results.At(results.Len()-1).Type(), builtinError) {
+		return ""
+	}
+
+	reserved := map[string]bool{"f": true, "err": true}
+	params := r.forwardParams(sig, reserved)
+
+	var resultTypes, resultValues, zeroDecls []string
+	for i := 0; i < results.Len()-1; i++ {
+		resultTypes = append(resultTypes, r.typeString(results.At(i).Type()))
+
+		name := uniqueName(fmt.Sprintf("r%d", i), reserved)
+		reserved[name] = true
+		zeroDecls = append(zeroDecls, fmt.Sprintf("\t\t\tvar %s %s\n", name, resultTypes[i]))
+		resultValues = append(resultValues, name)
+	}
+	resultTypes = append(resultTypes, r.typeString(builtinError))
+	resultValues = append(resultValues, "err")
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// Guard%s wraps f so that it returns the context's error instead of calling f once the context is done.\n", meth.Name()))
+	builder.WriteString(fmt.Sprintf("func Guard%s%s(f %s%s) %s%s {\n", meth.Name(), typeParams.decl, meth.Name(), typeParams.use, meth.Name(), typeParams.use))
+	builder.WriteString(fmt.Sprintf("\treturn func(%s)%s {\n", params.decl, resultList(resultTypes)))
+	builder.WriteString(fmt.Sprintf("\t\tif err := %s.Err(); err != nil {\n", params.names[0]))
+	builder.WriteString(strings.Join(zeroDecls, ""))
+	builder.WriteString(fmt.Sprintf("\t\t\treturn %s\n\t\t}\n", strings.Join(resultValues, ", ")))
+	builder.WriteString(fmt.Sprintf("\t\treturn f(%s)\n\t}\n}", params.args))
+	return builder.String()
+}
+
+// isContext returns true if t is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
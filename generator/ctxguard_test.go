@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateCtxGuard(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/ctxguard", EmitCtxGuard: true})["ctxguard_functypes.go"]
+
+	want := `// GuardPing wraps f so that it returns the context's error instead of calling f once the context is done.
+func GuardPing(f Ping) Ping {
+	return func(p0 context.Context) error {
+		if err := p0.Err(); err != nil {
+			return err
+		}
+		return f(p0)
+	}
+}
+`
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+	// Log has no error to return and Count doesn't take the context first.
+	for _, unguarded := range []string{"GuardLog", "GuardCount"} {
+		if strings.Contains(got, unguarded) {
+			t.Errorf("Generate() =\n%s\nwant no %s", got, unguarded)
+		}
+	}
+}
+
+func TestGenerateCtxGuardReturnsTheContextError(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata/ctxguard", EmitCtxGuard: true}, map[string]string{
+		"ctxguard_test.go": `package functypes
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	called := false
+	fetch := GuardFetch(func(ctx context.Context, id string) ([]byte, int, error) {
+		called = true
+		return []byte(id), 1, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if b, n, err := fetch(ctx, "a"); string(b) != "a" || n != 1 || err != nil {
+		t.Errorf("fetch() = %q, %d, %v, want the result of f", b, n, err)
+	}
+
+	called = false
+	cancel()
+	b, n, err := fetch(ctx, "a")
+	if !errors.Is(err, context.Canceled) || b != nil || n != 0 {
+		t.Errorf("fetch() = %q, %d, %v, want zero values and context.Canceled", b, n, err)
+	}
+	if called {
+		t.Errorf("f was called with a cancelled context")
+	}
+}
+`,
+	})
+}
@@ -0,0 +1,202 @@
+// Package generator provides the parts of functypes that are useful to other tools, such as comparing two generations of generated files.
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how a declaration changed between two generations.
+type ChangeKind string
+
+const (
+	// Added means the declaration only exists in the new generation.
+	Added ChangeKind = "added"
+	// Removed means the declaration only exists in the old generation.
+	Removed ChangeKind = "removed"
+	// Modified means the declaration exists in both generations, but its definition differs.
+	Modified ChangeKind = "modified"
+)
+
+// Change is a structural change to a single top-level declaration in a generated file.
+type Change struct {
+	Kind ChangeKind
+	// File is the name of the file containing the declaration, as given in the map passed to Diff.
+	File string
+	// Name is the name of the declaration. Methods are named <Receiver>.<Method>, like MyInterfaceFuncs.Foo.
+	Name string
+	// Old is the source of the declaration in the old generation, empty if it was added.
+	Old string
+	// New is the source of the declaration in the new generation, empty if it was removed.
+	New string
+}
+
+// Diff compares two generations of generated files, keyed by file name, and returns the declarations that were added, removed or modified, sorted by file and name.
+// Declarations are compared structurally: formatting and comments don't matter, only the declarations' source as printed by go/printer.
+// A file that can't be parsed is compared as a whole, and reported as a single Modified change with an empty Name if its content differs.
+func Diff(oldFiles, newFiles map[string][]byte) []Change {
+	var changes []Change
+
+	for _, file := range fileNames(oldFiles, newFiles) {
+		oldSrc, inOld := oldFiles[file]
+		newSrc, inNew := newFiles[file]
+
+		oldDecls, oldErr := topLevelDecls(file, oldSrc, inOld)
+		newDecls, newErr := topLevelDecls(file, newSrc, inNew)
+		if oldErr != nil || newErr != nil {
+			if !bytes.Equal(oldSrc, newSrc) {
+				changes = append(changes, Change{Kind: Modified, File: file, Old: string(oldSrc), New: string(newSrc)})
+			}
+			continue
+		}
+
+		for name, oldDecl := range oldDecls {
+			newDecl, ok := newDecls[name]
+			switch {
+			case !ok:
+				changes = append(changes, Change{Kind: Removed, File: file, Name: name, Old: oldDecl})
+			case oldDecl != newDecl:
+				changes = append(changes, Change{Kind: Modified, File: file, Name: name, Old: oldDecl, New: newDecl})
+			}
+		}
+
+		for name, newDecl := range newDecls {
+			if _, ok := oldDecls[name]; !ok {
+				changes = append(changes, Change{Kind: Added, File: file, Name: name, New: newDecl})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// fileNames returns the sorted union of the file names in both generations.
+func fileNames(oldFiles, newFiles map[string][]byte) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, files := range []map[string][]byte{oldFiles, newFiles} {
+		for name := range files {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// topLevelDecls parses the file and returns the source of each top-level declaration, without comments, keyed by declaration name.
+// A file that doesn't exist in a generation (exists is false) simply has no declarations.
+func topLevelDecls(fileName string, src []byte, exists bool) (map[string]string, error) {
+	decls := make(map[string]string)
+	if !exists {
+		return decls, nil
+	}
+
+	fset, parsed, err := parseTopLevelDecls(fileName, src)
+	if err != nil {
+		return nil, err
+	}
+	for _, decl := range parsed {
+		decls[declName(decl)] = printNode(fset, decl)
+	}
+	return decls, nil
+}
+
+// parseTopLevelDecls parses the file and returns its top-level declarations other than imports, in source order and without comments.
+// Grouped declarations like type ( A ...; B ... ) are split up into a declaration per spec, so each is compared on its own.
+func parseTopLevelDecls(fileName string, src []byte) (*token.FileSet, []ast.Decl, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, src, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var decls []ast.Decl
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			decl.Doc = nil
+			decls = append(decls, decl)
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					spec.Doc, spec.Comment = nil, nil
+				case *ast.ValueSpec:
+					spec.Doc, spec.Comment = nil, nil
+				}
+				decls = append(decls, &ast.GenDecl{Tok: decl.Tok, Specs: []ast.Spec{spec}})
+			}
+		}
+	}
+	return fset, decls, nil
+}
+
+// declName returns the name identifying a top-level declaration: the declared names for type, var and const declarations, like a, b for var a, b int, or the function name (<Receiver>.<Method> for methods).
+func declName(decl ast.Decl) string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return funcDeclName(decl)
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, spec.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// funcDeclName returns the name of a function declaration, or <Receiver>.<Method> for methods.
+func funcDeclName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if index, ok := recv.(*ast.IndexExpr); ok {
+		recv = index.X
+	}
+	if index, ok := recv.(*ast.IndexListExpr); ok {
+		recv = index.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + decl.Name.Name
+	}
+	return decl.Name.Name
+}
+
+// printNode returns the source of the AST node as printed by go/printer.
+func printNode(fset *token.FileSet, node ast.Node) string {
+	buf := &bytes.Buffer{}
+	if err := printer.Fprint(buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	const header = "package functypes\n\n"
+
+	tests := []struct {
+		name     string
+		oldFiles map[string][]byte
+		newFiles map[string][]byte
+		want     []Change
+	}{
+		{
+			name:     "identical files",
+			oldFiles: map[string][]byte{"a.go": []byte(header + "type Read func(p []byte) (int, error)\n")},
+			newFiles: map[string][]byte{"a.go": []byte(header + "type Read func(p []byte) (int, error)\n")},
+		},
+		{
+			name:     "formatting and comments don't matter",
+			oldFiles: map[string][]byte{"a.go": []byte(header + "// Read reads.\ntype Read func(p []byte) (int, error)\n")},
+			newFiles: map[string][]byte{"a.go": []byte(header + "type Read   func(p []byte)   (int, error) // reads\n")},
+		},
+		{
+			name:     "added, removed and modified declarations",
+			oldFiles: map[string][]byte{"a.go": []byte(header + "type Close func() error\ntype Read func(p []byte) (int, error)\n")},
+			newFiles: map[string][]byte{"a.go": []byte(header + "type Read func(p []byte) (n int, err error)\ntype Write func(p []byte) (int, error)\n")},
+			want: []Change{
+				{Kind: Removed, File: "a.go", Name: "Close", Old: "type Close func() error"},
+				{Kind: Modified, File: "a.go", Name: "Read", Old: "type Read func(p []byte) (int, error)", New: "type Read func(p []byte) (n int, err error)"},
+				{Kind: Added, File: "a.go", Name: "Write", New: "type Write func(p []byte) (int, error)"},
+			},
+		},
+		{
+			name:     "grouped declarations are compared one by one",
+			oldFiles: map[string][]byte{"a.go": []byte(header + "type (\n\tA func()\n\tB func()\n)\n")},
+			newFiles: map[string][]byte{"a.go": []byte(header + "type A func()\ntype B func(int)\n")},
+			want: []Change{
+				{Kind: Modified, File: "a.go", Name: "B", Old: "type B func()", New: "type B func(int)"},
+			},
+		},
+		{
+			name:     "methods are named after their receiver",
+			oldFiles: map[string][]byte{"a.go": []byte(header + "type Funcs struct{}\n\nfunc (f *Funcs) Read() {}\n")},
+			newFiles: map[string][]byte{"a.go": []byte(header + "type Funcs struct{}\n\nfunc (f *Funcs) Read() { panic(0) }\n")},
+			want: []Change{
+				{Kind: Modified, File: "a.go", Name: "Funcs.Read", Old: "func (f *Funcs) Read()\t{}", New: "func (f *Funcs) Read()\t{ panic(0) }"},
+			},
+		},
+		{
+			name:     "files only in one generation",
+			oldFiles: map[string][]byte{"old.go": []byte(header + "type A func()\n")},
+			newFiles: map[string][]byte{"new.go": []byte(header + "type B func()\n")},
+			want: []Change{
+				{Kind: Added, File: "new.go", Name: "B", New: "type B func()"},
+				{Kind: Removed, File: "old.go", Name: "A", Old: "type A func()"},
+			},
+		},
+		{
+			name:     "unparsable files are compared as a whole",
+			oldFiles: map[string][]byte{"a.go": []byte(header + "type A func(\n")},
+			newFiles: map[string][]byte{"a.go": []byte(header + "type A func()\n")},
+			want: []Change{
+				{Kind: Modified, File: "a.go", Old: header + "type A func(\n", New: header + "type A func()\n"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.oldFiles, tt.newFiles)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
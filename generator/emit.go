@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// forwardedParams describes the parameters of a signature in the two forms needed to emit code that accepts them and passes them on to another function.
+type forwardedParams struct {
+	// names are the parameter names used in the generated code.
+	names []string
+	// decl is the parameter list of the generated function, such as "a string, c ...string".
+	decl string
+	// args are the arguments passed on to the wrapped function, such as "a, c...".
+	args string
+}
+
+// forwardParams names the parameters of the signature and returns them in declaration and call form.
+// Named parameters keep their names as long as they don't collide with any of the reserved names used by the surrounding generated code, while unnamed or blank parameters (and colliding ones) get a synthesized name like p0.
+// All chosen names are added to reserved, so the caller can pick further local names without collisions.
+func (r *renderer) forwardParams(sig *types.Signature, reserved map[string]bool) forwardedParams {
+	params := sig.Params()
+	fp := forwardedParams{}
+
+	var decls, args []string
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+
+		name := param.Name()
+		if name == "" || name == "_" || reserved[name] {
+			name = uniqueName(fmt.Sprintf("p%d", i), reserved)
+		}
+		reserved[name] = true
+		fp.names = append(fp.names, name)
+
+		if sig.Variadic() && i == params.Len()-1 {
+			elem := param.Type().(*types.Slice).Elem()
+			decls = append(decls, fmt.Sprintf("%s ...%s", name, r.typeString(elem)))
+			args = append(args, name+"...")
+			continue
+		}
+
+		decls = append(decls, fmt.Sprintf("%s %s", name, r.typeString(param.Type())))
+		args = append(args, name)
+	}
+
+	fp.decl = strings.Join(decls, ", ")
+	fp.args = strings.Join(args, ", ")
+	return fp
+}
+
+// resultList returns the result list of a generated function returning the given types: nothing for zero results, "T" for one and "(T1, T2)" for more.
+func resultList(resultTypes []string) string {
+	switch len(resultTypes) {
+	case 0:
+		return ""
+	case 1:
+		return " " + resultTypes[0]
+	default:
+		return " (" + strings.Join(resultTypes, ", ") + ")"
+	}
+}
+
+// uniqueName returns name, or name with a numeric suffix if name is already taken.
+func uniqueName(name string, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", name, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// goEmitterName is the name of the built-in emitter, which renders the function types (and everything the emit options add) as Go code.
+const goEmitterName = "go"
+
+// Emitter writes the function types generated for an output file in a format of its own, instead of the built-in Go code.
+// Emitters are registered with RegisterEmitter and selected by name with Config.Emitter. The file they write is named <pkg>_functypes.<emitter name>, and the emit options adding Go code (like EmitAdapter) don't apply to them.
+// With Config.Jobs above 1, Emit can be called for several output files at the same time.
+type Emitter interface {
+	Emit(decls []GeneratedDecl, w io.Writer) error
+}
+
+// GeneratedDecl is a function type generated from an interface method, as passed to an Emitter.
+type GeneratedDecl struct {
+	// PkgPath is the import path of the package declaring the interface.
+	PkgPath string
+	// Interface is the name of the interface declaring the method.
+	Interface string
+	// Method is the name of the method.
+	Method string
+	// Name is the name of the function type, which is the name of the method unless it's prefixed with the interface name to keep it unique (see funcTypeName).
+	Name string
+	// Signature is the signature of the method.
+	Signature *types.Signature
+	// Go is the declaration of the function type as the built-in Go emitter renders it, like type Read func(p []byte) (n int, err error).
+	Go string
+}
+
+var (
+	emittersMu sync.Mutex
+	emitters   = make(map[string]Emitter)
+)
+
+// RegisterEmitter registers the emitter under the given name, for Config.Emitter (--emitter) to select it. It's meant to be called from an init function.
+// It panics if the name is already registered, or if it's go (the built-in emitter).
+func RegisterEmitter(name string, emitter Emitter) {
+	emittersMu.Lock()
+	defer emittersMu.Unlock()
+
+	if name == goEmitterName {
+		panic("functypes: the emitter name go is reserved for the built-in emitter")
+	}
+	if _, ok := emitters[name]; ok {
+		panic(fmt.Sprintf("functypes: the emitter %s is already registered", name))
+	}
+	emitters[name] = emitter
+}
+
+// lookupEmitter returns the registered emitter with the given name, or nil for the built-in go emitter.
+func lookupEmitter(name string) (Emitter, error) {
+	if name == goEmitterName {
+		return nil, nil
+	}
+
+	emittersMu.Lock()
+	defer emittersMu.Unlock()
+
+	emitter, ok := emitters[name]
+	if !ok {
+		names := []string{goEmitterName}
+		for registered := range emitters {
+			names = append(names, registered)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("--emitter %s is not registered, the registered emitters are: %s", name, strings.Join(names, ", "))
+	}
+	return emitter, nil
+}
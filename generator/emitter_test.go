@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// listEmitter lists the generated function types one per line.
+type listEmitter struct{}
+
+func (listEmitter) Emit(decls []GeneratedDecl, w io.Writer) error {
+	for _, decl := range decls {
+		if _, err := fmt.Fprintf(w, "%s.%s.%s: %s\n", decl.PkgPath, decl.Interface, decl.Name, decl.Go); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	RegisterEmitter("list", listEmitter{})
+}
+
+func TestGenerateCustomEmitter(t *testing.T) {
+	files := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", Emitter: "list", EmitAdapter: true})
+
+	want := `github.com/eaardal/functypes/testdata.MyInterface.Abc: type Abc func() (string, error)
+github.com/eaardal/functypes/testdata.MyInterface.Bar: type Bar func(a string) error
+github.com/eaardal/functypes/testdata.MyInterface.Foo: type Foo func(a string, b int, c ...string)
+`
+	if got := files["testdata_functypes.list"]; got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+	if _, ok := files["testdata_functypes.go"]; ok {
+		t.Errorf("Generate() wrote testdata_functypes.go, want only the emitter's file")
+	}
+}
+
+func TestEmitterIsValidated(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{cfg: Config{Emitter: "yaml"}, want: "--emitter yaml is not registered, the registered emitters are: go, list"},
+		{cfg: Config{Emitter: "list", Update: "MyInterface"}, want: "--update and --compat-with work on Go code and can't be used with the list emitter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			_, err := newGenerator(tt.cfg)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("newGenerator() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterEmitterPanics(t *testing.T) {
+	for _, name := range []string{goEmitterName, "list"} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterEmitter(%q) didn't panic", name)
+				}
+			}()
+			RegisterEmitter(name, listEmitter{})
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// builtinError is the type of the builtin error interface.
+var builtinError = types.Universe.Lookup("error").Type()
+
+// isErrorType returns true if t is the type treated as the error result of a method: either the --error-type itself or a pointer to it.
+func (g *generator) isErrorType(t types.Type) bool {
+	if types.Identical(t, g.errorType) {
+		return true
+	}
+	ptr, ok := t.(*types.Pointer)
+	return ok && g.errorType != builtinError && types.Identical(ptr.Elem(), g.errorType)
+}
+
+// returnsError returns true if the last result of the signature is the type treated as the error result.
+func (g *generator) returnsError(sig *types.Signature) bool {
+	results := sig.Results()
+	return results.Len() > 0 && g.isErrorType(results.At(results.Len()-1).Type())
+}
+
+// resolveErrorType looks up the type given to --error-type, in the form <import path or package name>.<TypeName>, in the loaded packages and the packages they import.
+// The type (or a pointer to it) must implement error, and it must be nillable when used directly (an interface or a pointer) so generated code can compare it to nil.
+func resolveErrorType(pkgs []*packages.Package, name string) (types.Type, error) {
+	dot := strings.LastIndex(name, ".")
+	if dot <= 0 || dot == len(name)-1 {
+		return nil, fmt.Errorf("--error-type %s must be in the form <import path>.<TypeName>", name)
+	}
+	pkgRef, typeName := name[:dot], name[dot+1:]
+
+	pkg := findTypesPackage(pkgs, pkgRef)
+	if pkg == nil {
+		return nil, fmt.Errorf("--error-type %s: found no package %s among the loaded packages and their imports", name, pkgRef)
+	}
+
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("--error-type %s: package %s has no type named %s", name, pkg.Path(), typeName)
+	}
+
+	errorIface := builtinError.Underlying().(*types.Interface)
+	typ := obj.Type()
+
+	if _, isIface := typ.Underlying().(*types.Interface); isIface {
+		if !types.Implements(typ, errorIface) {
+			return nil, fmt.Errorf("--error-type %s does not implement error", name)
+		}
+		return typ, nil
+	}
+
+	if !types.Implements(types.NewPointer(typ), errorIface) {
+		return nil, fmt.Errorf("--error-type %s does not implement error", name)
+	}
+	if !types.Implements(typ, errorIface) {
+		// Only *T implements error, so *T is what methods will return and what we compare against nil.
+		return typ, nil
+	}
+	if _, isPtr := typ.Underlying().(*types.Pointer); !isPtr {
+		return nil, fmt.Errorf("--error-type %s implements error with a value receiver, use an interface or pointer-receiver type so it can be compared to nil", name)
+	}
+	return typ, nil
+}
+
+// findOrLoadTypesPackage is like findTypesPackage, but loads the package by its import path if it isn't among the loaded packages and their imports. Returns nil if it can't be loaded.
+func findOrLoadTypesPackage(pkgs []*packages.Package, pkgRef string) *types.Package {
+	if pkg := findTypesPackage(pkgs, pkgRef); pkg != nil {
+		return pkg
+	}
+
+	loaded, err := packagesLoad(registerPackagesCfg, pkgRef)
+	if err != nil || len(loaded) == 0 || loaded[0].Types == nil || len(loaded[0].Errors) > 0 {
+		return nil
+	}
+	return loaded[0].Types
+}
+
+// findTypesPackage returns the package with the given import path (or, failing that, the given package name) among the loaded packages and everything they import.
+func findTypesPackage(pkgs []*packages.Package, pkgRef string) *types.Package {
+	var byName *types.Package
+	seen := make(map[*types.Package]bool)
+
+	var queue []*types.Package
+	for _, pkg := range pkgs {
+		queue = append(queue, pkg.Types)
+	}
+
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if pkg == nil || seen[pkg] {
+			continue
+		}
+		seen[pkg] = true
+
+		if pkg.Path() == pkgRef {
+			return pkg
+		}
+		if byName == nil && pkg.Name() == pkgRef {
+			byName = pkg
+		}
+		queue = append(queue, pkg.Imports()...)
+	}
+	return byName
+}
@@ -0,0 +1,64 @@
+package generator
+
+import "testing"
+
+func TestResolveErrorType(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		wantErr string
+	}{
+		{
+			name: "pointer receiver type by package name",
+			typ:  "domainerr.Error",
+		},
+		{
+			name: "pointer receiver type by import path",
+			typ:  "github.com/eaardal/functypes/testdata/domainerr.Error",
+		},
+		{
+			name:    "builtin error",
+			typ:     "error",
+			wantErr: "--error-type error must be in the form <import path>.<TypeName>",
+		},
+		{
+			name:    "type not implementing error",
+			typ:     "domainerr.Store",
+			wantErr: "--error-type domainerr.Store does not implement error",
+		},
+		{
+			name:    "unknown type",
+			typ:     "domainerr.Missing",
+			wantErr: "--error-type domainerr.Missing: package github.com/eaardal/functypes/testdata/domainerr has no type named Missing",
+		},
+		{
+			name:    "unknown package",
+			typ:     "missing.Error",
+			wantErr: "--error-type missing.Error: found no package missing among the loaded packages and their imports",
+		},
+	}
+
+	g, err := newGenerator(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := g.loadPackages("../testdata/domainerr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveErrorType(pkgs, tt.typ)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("resolveErrorType() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("resolveErrorType() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
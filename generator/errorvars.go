@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// errorVarName returns the name of the sentinel error var generated for the method of the named interface. The method name is capitalized like the one of its function type, for the unexported methods of --export-unexported.
+func errorVarName(ifaceName, methodName string) string {
+	return "Err" + ifaceName + capitalize(methodName)
+}
+
+// stringifyErrorVars returns a var block with a sentinel error var per converted method of the interface returning an error, of the method's error type (see --error-type). The vars are nil placeholders for the consumer to fill, like in tests asserting how errors are handled.
+// It returns an empty string if none of the methods return an error.
+func (r *renderer) stringifyErrorVars(named *types.Named, converted []*types.Func) string {
+	ifaceName := named.Obj().Name()
+
+	var vars []string
+	for _, meth := range converted {
+		sig := meth.Type().(*types.Signature)
+		if !r.returnsError(sig) {
+			continue
+		}
+		errType := sig.Results().At(sig.Results().Len() - 1).Type()
+		vars = append(vars, fmt.Sprintf("\t%s %s\n", errorVarName(ifaceName, meth.Name()), r.typeString(errType)))
+	}
+	if len(vars) == 0 {
+		return ""
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// Sentinel errors of the methods of %s, nil until they're assigned the errors to return or expect, like in tests.\n", ifaceName))
+	builder.WriteString("var (\n")
+	for _, v := range vars {
+		builder.WriteString(v)
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
@@ -0,0 +1,83 @@
+package generator
+
+import "testing"
+
+func TestGenerateErrorVars(t *testing.T) {
+	tests := []struct {
+		name      string
+		errorType string
+		want      string
+	}{
+		{
+			name: "builtin error",
+			want: `package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/domainerr"
+)
+
+type Load func(key string) (string, *domainerr.Error)
+type Save func(key string, value string) *domainerr.Error
+type Sync func(key string) (conflict *domainerr.Error, err error)
+
+// Sentinel errors of the methods of Syncer, nil until they're assigned the errors to return or expect, like in tests.
+var (
+	ErrSyncerSync error
+)
+`,
+		},
+		{
+			name:      "domain error",
+			errorType: "github.com/eaardal/functypes/testdata/domainerr.Error",
+			want: `package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/domainerr"
+)
+
+type Load func(key string) (string, *domainerr.Error)
+type Save func(key string, value string) *domainerr.Error
+
+// Sentinel errors of the methods of Store, nil until they're assigned the errors to return or expect, like in tests.
+var (
+	ErrStoreLoad *domainerr.Error
+	ErrStoreSave *domainerr.Error
+)
+
+type Sync func(key string) (conflict *domainerr.Error, err error)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/domainerr", EmitErrorVars: true, ErrorType: tt.errorType})["domainerr_functypes.go"]
+
+			want := generatedHeader + "\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateErrorVarsOfExportedUnexportedMethods(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/unexported", Include: "^Session$", ExportUnexported: true, EmitErrorVars: true})["unexported_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type ID func() string
+type Expire func(after int) (expired bool, err error)
+type Touch func()
+
+// Sentinel errors of the methods of Session, nil until they're assigned the errors to return or expect, like in tests.
+var (
+	ErrSessionExpire error
+)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"go/types"
+	"sort"
+)
+
+// explainMethod logs the decisions made rendering the function type of the method for --explain: the method's raw signature, how each package it references is qualified and imported, and the rendered declaration.
+func (r *renderer) explainMethod(ifaceName string, meth *types.Func, method string, qualified map[string]*importEntry) {
+	prefix := ifaceName + "." + meth.Name()
+	r.log.Infof("explain %s: raw signature: %s", prefix, types.TypeString(meth.Type(), nil))
+
+	paths := make([]string, 0, len(qualified))
+	for path := range qualified {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		entry := qualified[path]
+		if entry.name == entry.pkgName {
+			r.log.Infof("explain %s: package %s is qualified as %s and imported as %q", prefix, entry.pkgName, entry.name, path)
+		} else {
+			r.log.Infof("explain %s: package %s is qualified as %s because its name is taken, and imported as %s %q", prefix, entry.pkgName, entry.name, entry.name, path)
+		}
+	}
+	if len(paths) == 0 {
+		r.log.Infof("explain %s: no imports needed", prefix)
+	}
+
+	r.log.Infof("explain %s: rendered: %s", prefix, method)
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestGenerateExplain(t *testing.T) {
+	hook := test.NewLocal(logrus.StandardLogger())
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	generateFiles(t, Config{PkgPath: "../testdata/deep/lookup", Explain: "Resolver"})
+
+	var got []string
+	for _, entry := range hook.AllEntries() {
+		if strings.HasPrefix(entry.Message, "explain Resolver.A: ") {
+			got = append(got, entry.Message)
+		}
+	}
+
+	want := []string{
+		"explain Resolver.A: raw signature: func(id github.com/eaardal/functypes/testdata/deep/gamma/pkg/platform/storage/x.ID) github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x.ID",
+		`explain Resolver.A: package x is qualified as x2 because its name is taken, and imported as x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"`,
+		`explain Resolver.A: package x is qualified as x3 because its name is taken, and imported as x3 "github.com/eaardal/functypes/testdata/deep/gamma/pkg/platform/storage/x"`,
+		"explain Resolver.A: rendered: type A func(id x3.ID) x2.ID",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("explanation =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestGenerateExplainOnlyTheGivenInterface(t *testing.T) {
+	hook := test.NewLocal(logrus.StandardLogger())
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	generateFiles(t, Config{PkgPath: "../testdata", Explain: "MyInterface"})
+
+	explained := false
+	for _, entry := range hook.AllEntries() {
+		if strings.HasPrefix(entry.Message, "explain ") && !strings.HasPrefix(entry.Message, "explain MyInterface.") {
+			t.Errorf("logged %q, want only MyInterface to be explained", entry.Message)
+		}
+		if entry.Message == "explain MyInterface.Abc: no imports needed" {
+			explained = true
+		}
+	}
+	if !explained {
+		t.Errorf("MyInterface.Abc wasn't explained")
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/gcexportdata"
+	"golang.org/x/tools/go/packages"
+)
+
+// exportDataCfg is used to look up the export data of packages whose source isn't available. Asking for NeedExportFile makes go list compile the packages, so it's only used when we need it.
+var exportDataCfg = &packages.Config{
+	Mode: packages.NeedName | packages.NeedFiles | packages.NeedExportFile,
+}
+
+// loadExportFile loads a single package from a compiled export data file, such as a .a archive or a file from the build cache, with pkgPath as the package's import path.
+// The types read from export data carry their packages' import paths and names, so qualification and imports work just like for packages loaded from source.
+func loadExportFile(exportFile, pkgPath string) ([]*packages.Package, error) {
+	fset := token.NewFileSet()
+
+	typesPkg, err := readExportData(fset, exportFile, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*packages.Package{{
+		ID:      pkgPath,
+		Name:    typesPkg.Name(),
+		PkgPath: pkgPath,
+		Types:   typesPkg,
+		Fset:    fset,
+	}}, nil
+}
+
+// fillTypesFromExportData replaces the types of loaded packages without any source files (like binary-only packages) with the types found in their export data.
+// Packages with source files are left alone.
+func fillTypesFromExportData(pkgs []*packages.Package) error {
+	for _, pkg := range pkgs {
+		if len(pkg.CompiledGoFiles) > 0 || pkg.PkgPath == "" {
+			continue
+		}
+
+		exportPkgs, err := packagesLoad(exportDataCfg, pkg.PkgPath)
+		if err != nil {
+			return fmt.Errorf("failed to look up export data for %s: %v", pkg.PkgPath, err)
+		}
+		if len(exportPkgs) != 1 || exportPkgs[0].ExportFile == "" {
+			logrus.Debugf("found no export data for %s", pkg.PkgPath)
+			continue
+		}
+
+		typesPkg, err := readExportData(pkg.Fset, exportPkgs[0].ExportFile, pkg.PkgPath)
+		if err != nil {
+			return err
+		}
+
+		logrus.Debugf("loaded %s from export data %s because it has no source files", pkg.PkgPath, exportPkgs[0].ExportFile)
+		pkg.Types = typesPkg
+		pkg.Errors = nil
+	}
+	return nil
+}
+
+// readExportData reads the types of the package with the given import path from an export data file.
+func readExportData(fset *token.FileSet, exportFile, pkgPath string) (*types.Package, error) {
+	f, err := os.Open(exportFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export data %s: %v", exportFile, err)
+	}
+	defer f.Close()
+
+	reader, err := gcexportdata.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export data %s: %v", exportFile, err)
+	}
+
+	typesPkg, err := gcexportdata.Read(reader, fset, make(map[string]*types.Package), pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode export data %s: %v", exportFile, err)
+	}
+	return typesPkg, nil
+}
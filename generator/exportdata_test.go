@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerateFromExportFile(t *testing.T) {
+	const pkgPath = "github.com/eaardal/functypes/testdata/generics"
+
+	// Asking for the export file compiles the package into the build cache.
+	exportPkgs, err := packages.Load(exportDataCfg, pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exportPkgs) != 1 || exportPkgs[0].ExportFile == "" {
+		t.Fatalf("found no export data for %s", pkgPath)
+	}
+
+	got := generateFiles(t, Config{PkgPath: pkgPath, ExportFile: exportPkgs[0].ExportFile})["generics_functypes.go"]
+
+	// The types read from export data are qualified and imported just like those loaded from source.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/cache"
+)
+
+type Nested func(c *cache.Cache[string, *cache.Entry[[]byte]]) []*cache.Entry[int]
+type Use func(c *cache.Cache[string, int]) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
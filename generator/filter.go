@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+)
+
+// nameFilter decides which interfaces or methods get converted to function types, based on the regular expressions given in the --include and --exclude style flags.
+type nameFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// newNameFilter compiles the include and exclude expressions into a nameFilter. An empty expression disables filtering in that direction.
+// When caseInsensitive is true, the expressions are compiled with the (?i) flag so that e.g. "reader" matches "Reader".
+func newNameFilter(include, exclude string, caseInsensitive bool) (*nameFilter, error) {
+	filter := &nameFilter{}
+
+	if include != "" {
+		re, err := compileFilterExpr(include, caseInsensitive)
+		if err != nil {
+			return nil, err
+		}
+		filter.include = re
+	}
+
+	if exclude != "" {
+		re, err := compileFilterExpr(exclude, caseInsensitive)
+		if err != nil {
+			return nil, err
+		}
+		filter.exclude = re
+	}
+
+	return filter, nil
+}
+
+// compileFilterExpr compiles a single --include/--exclude style expression.
+func compileFilterExpr(expr string, caseInsensitive bool) (*regexp.Regexp, error) {
+	if caseInsensitive {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile filter expression %s: %v", expr, err)
+	}
+	return re, nil
+}
+
+// matches returns true if the name should be converted: it must match the include expression (if any) and must not match the exclude expression (if any).
+func (f *nameFilter) matches(name string) bool {
+	if f == nil {
+		return true
+	}
+	if f.include != nil && !f.include.MatchString(name) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(name) {
+		return false
+	}
+	return true
+}
+
+// declaredInFile returns true if the object is declared in the given file (see --file). A bare file name like foo.go matches a file with that name in any of the scanned packages, while a path is compared against the absolute path of the declaring file.
+func declaredInFile(fset *token.FileSet, obj types.Object, file string) bool {
+	if file == "" {
+		return true
+	}
+
+	declaringFile := fset.Position(obj.Pos()).Filename
+	if declaringFile == "" {
+		return false
+	}
+
+	if filepath.Base(file) == file {
+		return filepath.Base(declaringFile) == file
+	}
+
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	return filepath.Clean(declaringFile) == absFile
+}
@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestNameFilter(t *testing.T) {
+	tests := []struct {
+		name            string
+		include         string
+		exclude         string
+		caseInsensitive bool
+		matches         []string
+		skips           []string
+	}{
+		{
+			name:    "no expressions",
+			matches: []string{"Reader", "Writer"},
+		},
+		{
+			name:    "include",
+			include: "^Read",
+			matches: []string{"Reader", "ReadCloser"},
+			skips:   []string{"reader", "Writer"},
+		},
+		{
+			name:    "exclude",
+			exclude: "Closer$",
+			matches: []string{"Reader"},
+			skips:   []string{"ReadCloser", "Closer"},
+		},
+		{
+			name:            "case-insensitive include and exclude",
+			include:         "^reader",
+			exclude:         "CLOSER",
+			caseInsensitive: true,
+			matches:         []string{"Reader", "reader", "READER"},
+			skips:           []string{"ReaderCloser", "Writer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newNameFilter(tt.include, tt.exclude, tt.caseInsensitive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.matches {
+				if !filter.matches(name) {
+					t.Errorf("matches(%s) = false, want true", name)
+				}
+			}
+			for _, name := range tt.skips {
+				if filter.matches(name) {
+					t.Errorf("matches(%s) = true, want false", name)
+				}
+			}
+		})
+	}
+}
+
+func TestNameFilterInvalidExpression(t *testing.T) {
+	if _, err := newNameFilter("(", "", false); err == nil {
+		t.Error("newNameFilter() error = nil, want an error for the invalid expression")
+	}
+}
+
+func TestGenerateFilters(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^myinterface$", ExcludeMethods: "^bar$", CaseInsensitive: true})["testdata_functypes.go"]
+
+	want := generatedHeader + "\n\npackage functypes\n\ntype Abc func() (string, error)\ntype Foo func(a string, b int, c ...string)\n"
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateOnlyFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{
+			name: "file name",
+			file: "otherinterface.go",
+		},
+		{
+			name: "path",
+			file: "../testdata/otherinterface.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata", File: tt.file})["testdata_functypes.go"]
+
+			// Only the interfaces of otherinterface.go, not those of the package's other files.
+			want := generatedHeader + "\n\npackage functypes\n\ntype Bbb func()\ntype Aaa func()\n"
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateMethodFilter(t *testing.T) {
+	// Only methods returning an error.
+	returnsError := func(iface, method string, sig *types.Signature) bool {
+		results := sig.Results()
+		return results.Len() > 0 && types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+	}
+
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", ExcludeMethods: "^Bar$", MethodFilter: returnsError})["testdata_functypes.go"]
+
+	// Foo has no error result and Bar is excluded by name, so only Abc passes both filters.
+	want := generatedHeader + "\n\npackage functypes\n\ntype Abc func() (string, error)\n"
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateAccessorFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		getters bool
+		setters bool
+		want    string
+	}{
+		{
+			name:    "getters",
+			getters: true,
+			want:    "type Name func() string\ntype Size func() (int, error)\n",
+		},
+		{
+			name:    "setters",
+			setters: true,
+			want:    "type SetName func(name string)\ntype SetSize func(size int) error\n",
+		},
+		{
+			name:    "getters and setters",
+			getters: true,
+			setters: true,
+			want:    "type Name func() string\ntype SetName func(name string)\ntype SetSize func(size int) error\ntype Size func() (int, error)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/accessors", Getters: tt.getters, Setters: tt.setters})["accessors_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// resolveFromType looks up the type given to --from-type in the loaded packages: a type name, or <import path>.<TypeName> for a type of another package.
+func resolveFromType(pkgs []*packages.Package, name string) (*types.Named, error) {
+	var obj types.Object
+	if dot := strings.LastIndex(name, "."); dot > 0 {
+		pkg := findTypesPackage(pkgs, name[:dot])
+		if pkg == nil {
+			return nil, fmt.Errorf("--from-type %s: found no package %s among the loaded packages and their imports", name, name[:dot])
+		}
+		obj = pkg.Scope().Lookup(name[dot+1:])
+	} else {
+		for _, pkg := range pkgs {
+			if obj = pkg.Types.Scope().Lookup(name); obj != nil {
+				break
+			}
+		}
+	}
+
+	typeName, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("--from-type %s: found no type named %s", name, name)
+	}
+	named, ok := typeName.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("--from-type %s is not a defined type", name)
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("--from-type %s is not a struct type", name)
+	}
+	return named, nil
+}
+
+// fieldInterfaces returns the interfaces the struct type depends on through its fields, sorted by lock key: the interface types of its fields, and those of the fields of the struct types it holds, up to maxDepth levels of structs deep (the root's own fields being the first level).
+// Pointers, slices, arrays, maps and channels are looked through, so a []Handler field depends on Handler. Interfaces aren't walked further, only struct fields are.
+func fieldInterfaces(root *types.Named, maxDepth int) []*types.TypeName {
+	found := make(map[*types.TypeName]bool)
+	visited := make(map[*types.Named]bool)
+
+	var visit func(t types.Type, depth int)
+	visitFields := func(s *types.Struct, depth int) {
+		if depth > maxDepth {
+			return
+		}
+		for i := 0; i < s.NumFields(); i++ {
+			visit(s.Field(i).Type(), depth)
+		}
+	}
+	visit = func(t types.Type, depth int) {
+		switch t := t.(type) {
+		case *types.Pointer:
+			visit(t.Elem(), depth)
+		case *types.Slice:
+			visit(t.Elem(), depth)
+		case *types.Array:
+			visit(t.Elem(), depth)
+		case *types.Chan:
+			visit(t.Elem(), depth)
+		case *types.Map:
+			visit(t.Key(), depth)
+			visit(t.Elem(), depth)
+		case *types.Alias:
+			visit(types.Unalias(t), depth)
+		case *types.Struct:
+			visitFields(t, depth+1)
+		case *types.Named:
+			origin := t.Origin()
+			if visited[origin] {
+				return
+			}
+			visited[origin] = true
+
+			switch underlying := origin.Underlying().(type) {
+			case *types.Interface:
+				// The predeclared error is an interface too, but there's nothing to generate from it.
+				if origin.Obj().Pkg() != nil {
+					found[origin.Obj()] = true
+				}
+			case *types.Struct:
+				visitFields(underlying, depth+1)
+			}
+		}
+	}
+	visited[root] = true
+	visitFields(root.Underlying().(*types.Struct), 1)
+
+	ifaces := make([]*types.TypeName, 0, len(found))
+	for obj := range found {
+		ifaces = append(ifaces, obj)
+	}
+	sort.Slice(ifaces, func(i, j int) bool {
+		return lockKey(ifaces[i].Type().(*types.Named)) < lockKey(ifaces[j].Type().(*types.Named))
+	})
+	return ifaces
+}
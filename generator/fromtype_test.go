@@ -0,0 +1,72 @@
+package generator
+
+import "testing"
+
+func TestGenerateFromType(t *testing.T) {
+	tests := []struct {
+		name  string
+		depth int
+		want  string
+	}{
+		{
+			name: "default depth",
+			want: `package functypes
+
+import (
+	"time"
+)
+
+type Now func() time.Time
+type Notify func(msg string) error
+type Get func(key string) (string, error)
+type Write func(p []byte) (n int, err error)
+`,
+		},
+		{
+			// io.Writer is a field of Logger, one level below the fields of Service.
+			name:  "own fields only",
+			depth: 1,
+			want: `package functypes
+
+import (
+	"time"
+)
+
+type Now func() time.Time
+type Notify func(msg string) error
+type Get func(key string) (string, error)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/fromtype", FromType: "Service", FromTypeDepth: tt.depth})["fromtype_functypes.go"]
+
+			want := generatedHeader + "\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestFromTypeIsValidated(t *testing.T) {
+	tests := []struct {
+		fromType string
+		want     string
+	}{
+		{fromType: "Missing", want: "--from-type Missing: found no type named Missing"},
+		{fromType: "Store", want: "--from-type Store is not a struct type"},
+		{fromType: "example.com/missing.Service", want: "--from-type example.com/missing.Service: found no package example.com/missing among the loaded packages and their imports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fromType, func(t *testing.T) {
+			err := Generate(Config{PkgPath: "../testdata/fromtype", OutDir: t.TempDir(), FromType: tt.fromType})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
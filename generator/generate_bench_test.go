@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writeSyntheticPackages writes pkgCount packages with ifaceCount interfaces each into a new directory below the working directory, and returns the directory.
+// The packages have to be inside the module to be loaded, and not below a testdata directory, which package patterns skip. Each method has a name of its own, so no function types collide in the output directory the packages share.
+func writeSyntheticPackages(tb testing.TB, pkgCount, ifaceCount int) string {
+	tb.Helper()
+
+	dir, err := os.MkdirTemp(".", "synthetic")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { os.RemoveAll(dir) })
+
+	for p := 0; p < pkgCount; p++ {
+		src := &strings.Builder{}
+		fmt.Fprintf(src, "package p%d\n\nimport \"context\"\n\ntype Item struct{ ID string }\n", p)
+		for i := 0; i < ifaceCount; i++ {
+			fmt.Fprintf(src, "\ntype Service%d interface {\n", i)
+			fmt.Fprintf(src, "\tGet%dOf%d(ctx context.Context, id string) (*Item, error)\n", i, p)
+			fmt.Fprintf(src, "\tList%dOf%d(ctx context.Context, ids ...string) ([]Item, error)\n", i, p)
+			fmt.Fprintf(src, "\tDelete%dOf%d(id string) error\n", i, p)
+			src.WriteString("}\n")
+		}
+
+		pkgDir := filepath.Join(dir, fmt.Sprintf("p%d", p))
+		if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(pkgDir, fmt.Sprintf("p%d.go", p)), []byte(src.String()), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// benchmarkGenerate runs Generate b.N times with the config, reporting allocations. The log output is discarded.
+func benchmarkGenerate(b *testing.B, cfg Config) {
+	logrus.SetOutput(io.Discard)
+	b.Cleanup(func() { logrus.SetOutput(os.Stderr) })
+
+	cfg.OutDir = b.TempDir()
+	cfg.Force = true
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Generate(cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGenerate compares the generation modes on the same synthetic packages: a file per package, a subpackage per interface with --subpackages, and the files per package rendered concurrently with --jobs.
+func BenchmarkGenerate(b *testing.B) {
+	for _, size := range []struct{ pkgs, ifaces int }{{8, 10}, {8, 100}} {
+		dir := writeSyntheticPackages(b, size.pkgs, size.ifaces)
+		pattern := "./" + filepath.ToSlash(dir) + "/..."
+
+		modes := []struct {
+			name string
+			cfg  Config
+		}{
+			{name: "single-file", cfg: Config{PkgPath: pattern}},
+			{name: "per-interface", cfg: Config{PkgPath: pattern, Subpackages: true}},
+			{name: "concurrent", cfg: Config{PkgPath: pattern, Jobs: runtime.GOMAXPROCS(0)}},
+		}
+		for _, mode := range modes {
+			b.Run(fmt.Sprintf("mode=%s/packages=%d/interfaces=%d", mode.name, size.pkgs, size.ifaces), func(b *testing.B) {
+				benchmarkGenerate(b, mode.cfg)
+			})
+		}
+	}
+}
@@ -0,0 +1,674 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	dirPerm  = 0750
+	filePerm = 0666
+)
+
+// generator holds the state of a single Generate run.
+type generator struct {
+	cfg Config
+
+	// interfaceFilter and methodFilter are compiled from the include/exclude expressions in the config.
+	interfaceFilter, methodFilter *nameFilter
+
+	// errorType is the type treated as the error result of a method. It's the builtin error unless Config.ErrorType is set, see resolveErrorType.
+	errorType types.Type
+
+	// updateNames are the interfaces listed by Config.Update, or nil if all interfaces are generated.
+	updateNames map[string]bool
+
+	// registerFunc is the function the init function of --emit-init registers the function types with, see resolveRegisterFunc.
+	registerFunc *types.Func
+
+	// typeReplacements are parsed from Config.ReplaceType, and replacements are the types they resolve to by the key of the replaced type (see resolveTypeReplacements).
+	typeReplacements []typeReplacement
+	replacements     map[string]types.Type
+
+	// inlinePackages are the import paths of the packages whose types are inlined into the generated files, parsed from Config.InlinePackage.
+	inlinePackages map[string]bool
+
+	// routes are parsed from Config.Route and send interfaces to other output directories than Config.OutDir.
+	routes []route
+
+	// importCounts are the number of generated function types referencing each package, by import path. Written by writeImportsReport.
+	importCounts map[string]int
+
+	// locked are the lock entries of the generated interfaces, by lock key. Recorded in the --lock file by writeLock.
+	locked map[string]lockEntry
+	// previousLock are the entries of the --lock file as the previous run wrote it, read with --keep-aliases to detect renamed methods.
+	previousLock map[string]lockEntry
+
+	// generated are the function types rendered by the run, in order. Listed in the --emit-summary file by writeSummary.
+	generated []GeneratedDecl
+
+	// processed are the interfaces processed so far, by lock key. Counted against Config.MaxInterfaces.
+	processed map[string]bool
+
+	// methodOrigins are the interfaces whose function type of the --method method is rendered, in order, and methodCollisions the interfaces declaring it with a different signature, see sharesMethod.
+	methodOrigins    []methodOrigin
+	methodCollisions map[string]bool
+
+	// emitter is the custom Emitter selected with Config.Emitter, or nil for the built-in Go emitter.
+	emitter Emitter
+
+	// nameTemplate is parsed from Config.NameTemplate, or nil for the default names. See funcTypeName.
+	nameTemplate *template.Template
+
+	// collisions are the names of the function types colliding in each output directory, by output directory. See collidingMethods.
+	collisions map[string]collisions
+	// generatedDecls are the names declared by the generated files of each output directory, see generatedNames.
+	generatedDecls map[string]map[string]bool
+
+	// fromTypeInterfaces are the interfaces the --from-type struct depends on through its fields, which are processed instead of the interfaces of the loaded packages. See fieldInterfaces.
+	fromTypeInterfaces []*types.TypeName
+
+	// concreteTypes are the types of the scanned packages whose interfaces must be implemented by one of them with --only-implemented, see concreteTypes.
+	concreteTypes []*types.Named
+
+	// fset is the file set of the loaded packages, which --ast-fallback finds the source of methods by.
+	fset *token.FileSet
+
+	// localPkg is the package the generated files are rendered as part of with --qualify-relative-to, or nil for the functypes package.
+	localPkg *types.Package
+	// buildConstraint is the //go:build constraint of the files generated into localPkg, inherited from its files (see localBuildConstraint).
+	buildConstraint string
+
+	// files are the rendered files by output path. Nothing is written to disk until the whole run has been rendered, see writeFiles.
+	files map[string][]byte
+}
+
+// Generate loads the configured package(s), converts their interfaces to function types and writes the generated files to the output directory.
+// go/packages and go/types can panic on malformed input in rare cases. Such panics are recovered and returned as an error with the stack trace, so the CLI reports a clean failure and library users don't crash.
+func Generate(cfg Config) (err error) {
+	defer recoverPanic(&err)
+
+	g, err := newGenerator(cfg)
+	if err != nil {
+		return err
+	}
+	if g.cfg.Clean {
+		return g.clean()
+	}
+	if g.cfg.ImportsOnly {
+		return fmt.Errorf("--imports-only renders the import blocks instead of files and can't be written, use Render")
+	}
+
+	if err := g.render(); err != nil {
+		return err
+	}
+	if g.cfg.Adopt != "" {
+		return g.adopt()
+	}
+	if err := g.writeFiles(); err != nil {
+		return err
+	}
+	if g.cfg.ImportsReport != "" {
+		if err := g.writeImportsReport(g.cfg.ImportsReport); err != nil {
+			return err
+		}
+	}
+	if g.cfg.EmitSummary != "" {
+		if err := g.writeSummary(g.cfg.EmitSummary); err != nil {
+			return err
+		}
+	}
+	// With --frozen the lock file is only checked, never changed.
+	if g.cfg.Lock != "" && !g.cfg.Frozen {
+		return g.writeLock()
+	}
+	return nil
+}
+
+// Render is like Generate, but returns the generated files by output path instead of writing them, so nothing on disk changes (the --dry-run flag uses it).
+// This is handy in tests asserting what would be generated. Panics are recovered the same way as in Generate.
+func Render(cfg Config) (files map[string][]byte, err error) {
+	defer recoverPanic(&err)
+
+	g, err := newGenerator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if g.cfg.Clean {
+		return nil, fmt.Errorf("--clean deletes files and can't be rendered")
+	}
+	if g.cfg.Adopt != "" {
+		return nil, fmt.Errorf("--adopt reports on a hand-written file and can't be rendered")
+	}
+
+	if err := g.render(); err != nil {
+		return nil, err
+	}
+	return g.files, nil
+}
+
+// recoverPanic turns a panic into an error with the stack trace. It must be deferred.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("functypes panicked: %v\n%s", r, debug.Stack())
+	}
+}
+
+// newGenerator validates the config and prepares a generator for it.
+func newGenerator(cfg Config) (*generator, error) {
+	cfg = cfg.withDefaults()
+
+	if cfg.EmptyInterface != "any" && cfg.EmptyInterface != "interface{}" {
+		return nil, fmt.Errorf("--empty-interface must be any or interface{}, got %s", cfg.EmptyInterface)
+	}
+	if cfg.EmitInit && cfg.RegisterFunc == "" {
+		return nil, fmt.Errorf("--emit-init requires --register-func")
+	}
+	if cfg.Frozen && cfg.Lock == "" {
+		return nil, fmt.Errorf("--frozen requires --lock")
+	}
+	if cfg.FromType != "" && (isPackagePattern(cfg.PkgPath) || cfg.FromGoList) {
+		return nil, fmt.Errorf("--from-type generates the interfaces a single type depends on, point --pkg-path at the package declaring it instead of using a package pattern or --from-go-list")
+	}
+	if cfg.Jobs < 0 {
+		return nil, fmt.Errorf("--jobs must be at least 1, got %d", cfg.Jobs)
+	}
+	if cfg.FromTypeDepth < 0 {
+		return nil, fmt.Errorf("--from-type-depth must be at least 1, got %d", cfg.FromTypeDepth)
+	}
+	if cfg.KeepAliases && cfg.Lock == "" {
+		return nil, fmt.Errorf("--keep-aliases requires --lock, which records the method signatures it detects renamed methods by")
+	}
+	if cfg.FailOnUnexportedMethods && cfg.SkipUnexportedMethods {
+		return nil, fmt.Errorf("--fail-on-unexported-methods and --skip-unexported-methods can't be used together")
+	}
+	if cfg.TypeAlias && cfg.EmitNilChecks {
+		return nil, fmt.Errorf("--type-alias can't be used with --emit-nil-checks, since methods can't be declared on an alias of a function signature")
+	}
+	if cfg.ExportUnexported && (cfg.FailOnUnexportedMethods || cfg.SkipUnexportedMethods) {
+		return nil, fmt.Errorf("--export-unexported can't be used with --fail-on-unexported-methods or --skip-unexported-methods")
+	}
+	if cfg.ProvenanceSource != "embedder" && cfg.ProvenanceSource != "definer" {
+		return nil, fmt.Errorf("--provenance-source must be embedder or definer, got %s", cfg.ProvenanceSource)
+	}
+	if cfg.AggregateMode != "expand" && cfg.AggregateMode != "skip" && cfg.AggregateMode != "reference" {
+		return nil, fmt.Errorf("--aggregate-mode must be expand, skip or reference, got %s", cfg.AggregateMode)
+	}
+	if cfg.StubError != "nil" && cfg.StubError != "sentinel" {
+		return nil, fmt.Errorf("--stub-error must be nil or sentinel, got %s", cfg.StubError)
+	}
+
+	interfaceFilter, err := newNameFilter(cfg.Include, cfg.Exclude, cfg.CaseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	methodFilter, err := newNameFilter(cfg.IncludeMethods, cfg.ExcludeMethods, cfg.CaseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := parseRoutes(cfg.Route)
+	if err != nil {
+		return nil, err
+	}
+	typeReplacements, err := parseTypeReplacements(cfg.ReplaceType)
+	if err != nil {
+		return nil, err
+	}
+
+	var nameTemplate *template.Template
+	if cfg.NameTemplate != "" {
+		if cfg.FlattenUnique {
+			return nil, fmt.Errorf("--flatten-unique can't be used with --name-template, whose names are used as they are")
+		}
+		if nameTemplate, err = parseNameTemplate(cfg.NameTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	emitter, err := lookupEmitter(cfg.Emitter)
+	if err != nil {
+		return nil, err
+	}
+	if emitter != nil && cfg.ImportsOnly {
+		return nil, fmt.Errorf("--imports-only renders Go import blocks and can't be used with the %s emitter", cfg.Emitter)
+	}
+	if emitter != nil && (cfg.Update != "" || cfg.CompatWith != "") {
+		return nil, fmt.Errorf("--update and --compat-with work on Go code and can't be used with the %s emitter", cfg.Emitter)
+	}
+
+	if cfg.SamePackage && (isPackagePattern(cfg.PkgPath) || cfg.FromGoList || cfg.ExportFile != "" || cfg.QualifyRelativeTo != "" || cfg.Route != "" || cfg.Subpackages) {
+		return nil, fmt.Errorf("--same-package renders into a single package loaded from source and can't be used with a package pattern, --from-go-list, --export-file, --qualify-relative-to, --route or --subpackages")
+	}
+	if cfg.Subpackages && (cfg.Route != "" || cfg.QualifyRelativeTo != "" || cfg.CompatWith != "" || emitter != nil) {
+		return nil, fmt.Errorf("--subpackages decides the output directories and package names itself and can't be used with --route, --qualify-relative-to, --compat-with or a custom --emitter")
+	}
+
+	if cfg.FromGoList && (cfg.ExportFile != "" || cfg.SeedFile != "") {
+		return nil, fmt.Errorf("--from-go-list loads the listed packages from source and can't be used with --export-file or --seed-file")
+	}
+
+	if cfg.AdapterReceiver != "pointer" && cfg.AdapterReceiver != "value" && cfg.AdapterReceiver != "both" {
+		return nil, fmt.Errorf("--adapter-receiver must be pointer, value or both, got %s", cfg.AdapterReceiver)
+	}
+
+	if cfg.MaxInterfaces < 0 {
+		return nil, fmt.Errorf("--max-interfaces must not be negative, got %d", cfg.MaxInterfaces)
+	}
+
+	if cfg.MethodExpressions {
+		if conflicts := methodExpressionConflicts(cfg); conflicts != "" {
+			return nil, fmt.Errorf("--method-expressions can't be used with %s, which generate code for function types with the signature of the methods", conflicts)
+		}
+	}
+
+	var updateNames map[string]bool
+	if cfg.Update != "" {
+		if cfg.CompatWith != "" {
+			return nil, fmt.Errorf("--update and --compat-with can't be used together")
+		}
+		updateNames = make(map[string]bool)
+		for _, name := range strings.Split(cfg.Update, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				updateNames[name] = true
+			}
+		}
+	}
+
+	return &generator{
+		cfg:              cfg,
+		interfaceFilter:  interfaceFilter,
+		methodFilter:     methodFilter,
+		errorType:        builtinError,
+		updateNames:      updateNames,
+		routes:           routes,
+		typeReplacements: typeReplacements,
+		inlinePackages:   parseInlinePackages(cfg.InlinePackage),
+		emitter:          emitter,
+		nameTemplate:     nameTemplate,
+		importCounts:     make(map[string]int),
+		locked:           make(map[string]lockEntry),
+		processed:        make(map[string]bool),
+		methodCollisions: make(map[string]bool),
+		files:            make(map[string][]byte),
+	}, nil
+}
+
+// render loads the packages and renders a file per package (or one file for a single package directory) into g.files.
+func (g *generator) render() error {
+	pkgs, err := g.loadPackages(g.cfg.PkgPath)
+	if err != nil {
+		return err
+	}
+
+	if g.cfg.KeepAliases {
+		if g.previousLock, err = readLock(g.cfg.Lock); err != nil {
+			return err
+		}
+	}
+	logrus.Debugf("packages loaded: %+v", pkgs)
+
+	// The scanned package is only known once loaded, and it has to be known before excluding the output directory, which is its directory.
+	if g.cfg.SamePackage && len(pkgs) > 0 {
+		g.cfg.QualifyRelativeTo = pkgs[0].PkgPath
+	}
+
+	for _, outDir := range g.outDirs() {
+		pkgs, err = excludeOutputDir(pkgs, outDir, g.cfg.QualifyRelativeTo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := checkPackageErrors(pkgs, g.cfg.BestEffort); err != nil {
+		return err
+	}
+	if len(pkgs) > 0 {
+		g.fset = pkgs[0].Fset
+	}
+	checkImportComments(pkgs)
+
+	if g.cfg.ErrorType != "" {
+		g.errorType, err = resolveErrorType(pkgs, g.cfg.ErrorType)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.cfg.QualifyRelativeTo != "" {
+		g.localPkg = findOrLoadTypesPackage(pkgs, g.cfg.QualifyRelativeTo)
+		if g.localPkg == nil {
+			return fmt.Errorf("--qualify-relative-to: failed to load package %s", g.cfg.QualifyRelativeTo)
+		}
+		g.buildConstraint = g.localBuildConstraint(pkgs)
+	}
+
+	if len(g.typeReplacements) > 0 {
+		g.replacements, err = resolveTypeReplacements(pkgs, g.typeReplacements)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.cfg.Subpackages {
+		g.routes = subpackageRoutes(pkgs, g.cfg.OutDir)
+	}
+
+	if g.cfg.FromType != "" {
+		root, err := resolveFromType(pkgs, g.cfg.FromType)
+		if err != nil {
+			return err
+		}
+		g.fromTypeInterfaces = fieldInterfaces(root, g.cfg.FromTypeDepth)
+		logrus.Debugf("--from-type %s depends on the interfaces %v", g.cfg.FromType, g.fromTypeInterfaces)
+	}
+
+	if g.cfg.OnlyImplemented {
+		g.concreteTypes = concreteTypes(pkgs)
+	}
+
+	if g.cfg.EmitInit {
+		g.registerFunc, err = resolveRegisterFunc(pkgs, g.cfg.RegisterFunc)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.collisions, err = g.collidingMethods(pkgs); err != nil {
+		return err
+	}
+
+	// A pattern like ./... can match many packages, in which case each package gets its own output file named after the package.
+	// Packages of the same name, like a/util and b/util, share their output file, so they're rendered into it together rather than one replacing the other.
+	if g.loadsManyPackages() {
+		if err := g.generateGroups(groupByName(pkgs)); err != nil {
+			return err
+		}
+	} else if err := g.generate(pkgs, outputName(g.cfg.PkgPath, pkgs)); err != nil {
+		return err
+	}
+
+	if err := g.checkMethodCollisions(); err != nil {
+		return err
+	}
+
+	// The import blocks of --imports-only aren't Go files to check.
+	if !g.cfg.ImportsOnly {
+		if err := g.checkIdentifiers(); err != nil {
+			return err
+		}
+	}
+
+	if g.cfg.Frozen {
+		if err := g.checkLock(); err != nil {
+			return err
+		}
+	}
+
+	if g.cfg.Validate && !g.cfg.ImportsOnly {
+		return g.validate(pkgs)
+	}
+	return nil
+}
+
+// generate converts the interfaces found in the given packages to function types and renders them as <pkgName>_functypes.go in the output directory, or in the directories they're routed to with --route.
+func (g *generator) generate(pkgs []*packages.Package, pkgName string) error {
+	for _, outDir := range g.outDirs() {
+		if err := g.generateInto(pkgs, pkgName, outDir); err != nil {
+			return err
+		}
+	}
+	// The import blocks of --imports-only have no declarations to re-export.
+	if g.cfg.Subpackages && !g.cfg.ImportsOnly {
+		return g.generateAggregator(pkgs, pkgName)
+	}
+	return nil
+}
+
+// generateInto renders <pkgName>_functypes.go in the given output directory, containing the function types of the interfaces routed to that directory (see outDirFor).
+func (g *generator) generateInto(pkgs []*packages.Package, pkgName, outDir string) error {
+	referenced, err := g.collectImports(pkgs, outDir)
+	if err != nil {
+		return err
+	}
+
+	r := g.newRenderer(outDir)
+	r.imports.assignInOrder(referenced)
+
+	bodyBuilder := &strings.Builder{}
+	adapters, err := r.processPackages(pkgs, bodyBuilder)
+	if err != nil {
+		return err
+	}
+	if len(r.renderErrors) > 0 {
+		return fmt.Errorf("failed to render the function types of %d methods of %s, which is a bug in functypes:\n\t%s", len(r.renderErrors), pkgName, strings.Join(r.renderErrors, "\n\t"))
+	}
+	for path, count := range r.importCounts {
+		g.importCounts[path] += count
+	}
+	for key, entry := range r.locked {
+		g.locked[key] = entry
+	}
+	g.generated = append(g.generated, r.decls...)
+
+	if g.emitter != nil {
+		return g.emit(r.decls, pkgName, outDir)
+	}
+	if r.usesErrNotImplemented {
+		bodyBuilder.WriteString(r.stringifyErrNotImplemented() + "\n")
+	}
+	if len(r.registered) > 0 {
+		bodyBuilder.WriteString(r.stringifyInit() + "\n")
+	}
+	if len(r.inlined) > 0 {
+		inlined, err := r.stringifyInlined()
+		if err != nil {
+			return err
+		}
+		bodyBuilder.WriteString(inlined)
+	}
+
+	// With routes, a directory nothing was routed to doesn't get an empty file. With --update, a file without any of the updated interfaces is left alone. With a package pattern, the packages without interfaces to convert don't get a file either.
+	if (len(g.routes) > 0 || g.updateNames != nil || g.loadsManyPackages()) && strings.TrimSpace(bodyBuilder.String()) == "" {
+		logrus.Debugf("skipping %s because none of the interfaces of %s are rendered to it", outDir, pkgName)
+		return nil
+	}
+
+	content, err := renderFile(g.packageNameFor(outDir), r.imports, bodyBuilder.String())
+	if err != nil {
+		return err
+	}
+
+	outFileName := fmt.Sprintf("%s_functypes.go", pkgName)
+	outFilePath := path.Join(outDir, outFileName)
+	logrus.Debugf("outFilePath: %s", outFilePath)
+
+	checkInternalImports(pkgs, outDir, outFilePath, r.imports)
+
+	if g.cfg.ImportsOnly {
+		g.files[outFilePath] = []byte(r.imports.block())
+		return nil
+	}
+
+	// With a package pattern, the previously generated file only applies to the output file with the same name. Routed files are never compared to it.
+	if g.cfg.CompatWith != "" && outDir == g.cfg.OutDir && (!g.loadsManyPackages() || filepath.Base(g.cfg.CompatWith) == outFileName) {
+		shims, err := readCompatShims(g.cfg.CompatWith, []byte(content))
+		if err != nil {
+			return err
+		}
+		if shims != "" {
+			content, err = renderFile(g.packageNameFor(outDir), r.imports, bodyBuilder.String()+"\n"+shims)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if g.updateNames != nil {
+		updated, err := readUpdatedFile(outFilePath, g.packageNameFor(outDir), []byte(content))
+		if err != nil {
+			return err
+		}
+		content = string(updated)
+	}
+
+	g.files[outFilePath] = []byte(withBuildConstraint(content, g.buildConstraint))
+
+	if !g.cfg.EmitTest {
+		adapters = nil
+	}
+
+	// The generated test covers all adapters and examples of the file, so it's only rewritten by a full generation.
+	if (len(adapters) > 0 || len(r.examples) > 0) && g.updateNames != nil {
+		logrus.Debugf("not updating the test file of %s with --update", outFilePath)
+	} else if len(adapters) > 0 || len(r.examples) > 0 {
+		testFilePath := path.Join(outDir, fmt.Sprintf("%s_functypes_test.go", pkgName))
+		testContent, err := g.testFileContent(g.packageNameFor(outDir), adapters, r.examples)
+		if err != nil {
+			return err
+		}
+		g.files[testFilePath] = []byte(withBuildConstraint(testContent, g.buildConstraint))
+	}
+	return nil
+}
+
+// emit renders the function types of an output file with the custom emitter, as <pkgName>_functypes.<emitter name> in the output directory.
+func (g *generator) emit(decls []GeneratedDecl, pkgName, outDir string) error {
+	if len(g.routes) > 0 && len(decls) == 0 {
+		logrus.Debugf("skipping %s because none of the interfaces of %s are rendered to it", outDir, pkgName)
+		return nil
+	}
+
+	outFilePath := path.Join(outDir, fmt.Sprintf("%s_functypes.%s", pkgName, g.cfg.Emitter))
+	buf := &bytes.Buffer{}
+	if err := g.emitter.Emit(decls, buf); err != nil {
+		return fmt.Errorf("the %s emitter failed to emit %s: %v", g.cfg.Emitter, outFilePath, err)
+	}
+	g.files[outFilePath] = buf.Bytes()
+	return nil
+}
+
+// checkSymlinkedDir returns an error if the directory path is a symlink which doesn't resolve to a directory, like a symlink to a directory that was removed.
+// Writing through a symlink to a directory works like writing to the directory itself, but for a broken symlink os.MkdirAll fails as if the directory existed, which is confusing.
+func checkSymlinkedDir(dirPath string) error {
+	info, err := os.Lstat(dirPath)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+
+	target, _ := os.Readlink(dirPath)
+	resolved, err := filepath.EvalSymlinks(dirPath)
+	if err != nil {
+		return fmt.Errorf("the output directory %s is a broken symlink to %s: %v", dirPath, target, err)
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+		return fmt.Errorf("the output directory %s is a symlink to %s, which is not a directory", dirPath, target)
+	}
+	return nil
+}
+
+// renderFile assembles a generated .go file from its sections: the generated-code comment, the package line, the import block for the packages referenced by the declarations (if any), and the declarations themselves.
+// This is the only place deciding the spacing between the sections, which is always exactly one blank line, and the result is run through gofmt so the file doesn't change if someone formats it.
+func renderFile(pkgName string, imports *importSet, body string) (string, error) {
+	sections := []string{generatedHeader, packageLine(pkgName)}
+	if block := strings.TrimSpace(imports.block()); block != "" {
+		sections = append(sections, block)
+	}
+	if body = strings.TrimSpace(body); body != "" {
+		sections = append(sections, body)
+	}
+
+	src := strings.Join(sections, "\n\n") + "\n"
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %v\n%s", err, src)
+	}
+	return string(formatted), nil
+}
+
+// defaultPackageName is the name of the package the generated files belong to, unless they're generated into another package with --qualify-relative-to.
+const defaultPackageName = "functypes"
+
+// packageLine returns the package clause of a generated file in the named package.
+func packageLine(pkgName string) string {
+	return "package " + pkgName
+}
+
+// packageName returns the name of the package the generated files belong to: the --qualify-relative-to package, or functypes.
+func (g *generator) packageName() string {
+	if g.localPkg != nil {
+		return g.localPkg.Name()
+	}
+	return defaultPackageName
+}
+
+// localPath returns the import path of the --qualify-relative-to package, or an empty string if the generated files belong to the functypes package.
+func (g *generator) localPath() string {
+	if g.localPkg != nil {
+		return g.localPkg.Path()
+	}
+	return ""
+}
+
+// writeFiles writes the rendered files, and records them in the manifest of each output directory.
+func (g *generator) writeFiles() error {
+	paths := make([]string, 0, len(g.files))
+	for outFilePath := range g.files {
+		paths = append(paths, outFilePath)
+	}
+	sort.Strings(paths)
+
+	written := make(map[string][]string)
+	for _, outFilePath := range paths {
+		if err := g.writeOutput(outFilePath, g.files[outFilePath]); err != nil {
+			return err
+		}
+		logrus.Infof("saved %s", outFilePath)
+
+		dirPath := filepath.Dir(outFilePath)
+		written[dirPath] = append(written[dirPath], filepath.Base(outFilePath))
+	}
+
+	for outDir, names := range written {
+		if err := writeManifest(outDir, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeOutput will ensure the directories to the output file exists and create the output file. If the file exists, it will be overwritten as long as checkOverwrite allows it.
+func (g *generator) writeOutput(outFilePath string, content []byte) error {
+	dirPath := filepath.Dir(outFilePath)
+
+	if err := checkSymlinkedDir(dirPath); err != nil {
+		return err
+	}
+
+	if err := g.checkOverwrite(outFilePath); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dirPath, dirPerm); err != nil {
+		return fmt.Errorf("mkdir %s with perm %d: %w", dirPath, dirPerm, err)
+	}
+
+	if err := os.WriteFile(outFilePath, content, filePerm); err != nil {
+		return fmt.Errorf("write %s with perm %d: %w", outFilePath, filePerm, err)
+	}
+
+	return nil
+}
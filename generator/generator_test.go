@@ -0,0 +1,261 @@
+package generator
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestMain(m *testing.M) {
+	logrus.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// generateFiles runs Generate with the config into a temporary output directory, unless the config sets one, and returns the content of the written files by name.
+func generateFiles(t *testing.T, cfg Config) map[string]string {
+	t.Helper()
+
+	if cfg.OutDir == "" {
+		cfg.OutDir = t.TempDir()
+	}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return readFiles(t, cfg.OutDir)
+}
+
+// readFiles returns the content of the files in the directory by name.
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(content)
+	}
+	return files
+}
+
+// goTestGenerated runs Generate with the config into a new directory inside the module, adds the test files to it and runs go test on the generated package.
+// The output directory has to be inside the module so the generated code can import the scanned packages.
+func goTestGenerated(t *testing.T, cfg Config, testFiles map[string]string) {
+	t.Helper()
+
+	outDir, err := os.MkdirTemp("../testdata", "gotest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(outDir) })
+
+	cfg.OutDir = outDir
+	generateFiles(t, cfg)
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(outDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := exec.Command("go", "test", outDir).CombinedOutput()
+	if err != nil {
+		t.Errorf("go test of the generated files failed: %v\n%s", err, out)
+	}
+}
+
+// warnings records the warnings logged during the test. Call the returned function to get the messages logged so far.
+func warnings(t *testing.T) func() []string {
+	t.Helper()
+
+	hook := test.NewLocal(logrus.StandardLogger())
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	return func() []string {
+		var messages []string
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.WarnLevel {
+				messages = append(messages, entry.Message)
+			}
+		}
+		return messages
+	}
+}
+
+func TestRenderFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		imports []string
+		body    string
+		want    string
+	}{
+		{
+			name: "declarations only",
+			body: "type Close func() error\n",
+			want: generatedHeader + "\n\npackage functypes\n\ntype Close func() error\n",
+		},
+		{
+			name:    "imports and declarations",
+			imports: []string{"context"},
+			body:    "\n\ntype Close func(ctx context.Context) error\n\n\n",
+			want:    generatedHeader + "\n\npackage functypes\n\nimport (\n\t\"context\"\n)\n\ntype Close func(ctx context.Context) error\n",
+		},
+		{
+			name: "no declarations",
+			want: generatedHeader + "\n\npackage functypes\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports := newImportSet()
+			for _, path := range tt.imports {
+				imports.add(path, path)
+			}
+
+			got, err := renderFile(defaultPackageName, imports, tt.body)
+			if err != nil {
+				t.Fatalf("renderFile() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderFile() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPanicsAreReturnedAsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(cfg Config) error
+	}{
+		{
+			name: "Generate",
+			run:  Generate,
+		},
+		{
+			name: "Render",
+			run: func(cfg Config) error {
+				_, err := Render(cfg)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			load := packagesLoad
+			packagesLoad = func(*packages.Config, ...string) ([]*packages.Package, error) {
+				panic("malformed input")
+			}
+			t.Cleanup(func() { packagesLoad = load })
+
+			err := tt.run(Config{PkgPath: "../testdata/geo", OutDir: t.TempDir()})
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), "functypes panicked: malformed input") {
+				t.Errorf("expected the panic value in the error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "goroutine") {
+				t.Errorf("expected the stack trace in the error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want map[string]string
+	}{
+		{
+			name: "function types",
+			cfg:  Config{PkgPath: "../testdata/embedded"},
+			want: map[string]string{
+				"embedded_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type CloserClose func() error
+type ReadCloserClose func() error
+type ReadCloserName func() string
+type ReadCloserRead func(p []byte) (n int, err error)
+`,
+			},
+		},
+		{
+			name: "adapters",
+			cfg:  Config{PkgPath: "../testdata/blanks", EmitAdapter: true},
+			want: map[string]string{
+				"blanks_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type Close func() error
+type Scan func(_ []byte, n int) (_ int, err error)
+type Skip func(int) (int, error)
+
+// ScannerFuncs implements Scanner by delegating each method to the function in the corresponding field.
+type ScannerFuncs struct {
+	CloseFunc Close
+	ScanFunc  Scan
+	SkipFunc  Skip
+}
+
+func (a *ScannerFuncs) Close() error {
+	return a.CloseFunc()
+}
+
+func (a *ScannerFuncs) Scan(p0 []byte, n int) (int, error) {
+	return a.ScanFunc(p0, n)
+}
+
+func (a *ScannerFuncs) Skip(p0 int) (int, error) {
+	return a.SkipFunc(p0)
+}
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := filepath.Join(t.TempDir(), "functypes")
+			tt.cfg.OutDir = outDir
+
+			files, err := Render(tt.cfg)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			got := make(map[string]string)
+			for outFilePath, content := range files {
+				got[strings.TrimPrefix(outFilePath, outDir+"/")] = string(content)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render() =\n%v\nwant\n%v", got, tt.want)
+			}
+
+			if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+				t.Errorf("expected Render not to create %s, got %v", outDir, err)
+			}
+		})
+	}
+}
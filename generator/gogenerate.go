@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// defaultPkgPath returns the --pkg-path used when none is given: the directory of the file with the //go:generate directive when run by go generate, or the current directory otherwise.
+// go generate sets $GOFILE and $GOPACKAGE and runs the command in the package's directory, so //go:generate functypes works without any flags.
+func defaultPkgPath() string {
+	if goFile := os.Getenv("GOFILE"); goFile != "" && os.Getenv("GOPACKAGE") != "" {
+		return filepath.Dir(goFile)
+	}
+	return "."
+}
+
+// outputName returns the name a single package directory's output file is named after (<name>_functypes.go).
+// That's the name of the loaded package, which often differs from its directory's name, like package foo in a v2 directory. Failing that it's $GOPACKAGE when go generate runs us in the package's directory, or else the name of the directory. Relative paths like . are resolved first, so they don't end up in the file name.
+func outputName(pkgPath string, pkgs []*packages.Package) string {
+	if len(pkgs) == 1 && pkgs[0].Name != "" {
+		return pkgs[0].Name
+	}
+	if pkg := os.Getenv("GOPACKAGE"); pkg != "" && filepath.Clean(pkgPath) == "." {
+		return pkg
+	}
+	dir := pkgPath
+	if abs, err := filepath.Abs(pkgPath); err == nil {
+		dir = abs
+	}
+	return sanitizeName(filepath.Base(dir))
+}
+
+// sanitizeName turns a directory name into an identifier to name the output file after, like 123-foo into pkg123_foo, with a warning.
+// A directory name can be anything, including dashes and spaces, while the package names output files are named after otherwise are always identifiers.
+func sanitizeName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if sanitized == "" || unicode.IsDigit([]rune(sanitized)[0]) {
+		sanitized = "pkg" + sanitized
+	}
+
+	if sanitized != name {
+		logrus.Warnf("the directory name %s is not a valid package name, naming the output file after %s instead", name, sanitized)
+	}
+	return sanitized
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateInGoGenerate(t *testing.T) {
+	t.Chdir("../testdata/gogenerate")
+	t.Setenv("GOFILE", "gogenerate.go")
+	t.Setenv("GOPACKAGE", "gogenerate")
+
+	if got := (Config{}).withDefaults().PkgPath; got != "." {
+		t.Errorf("withDefaults().PkgPath = %q, want %q", got, ".")
+	}
+
+	files := generateFiles(t, Config{})
+	got, ok := files["gogenerate_functypes.go"]
+	if !ok {
+		t.Fatalf("Generate() wrote %v, want gogenerate_functypes.go", files)
+	}
+	if want := "type Notify func(message string) error\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("gogenerate_functypes.go =\n%s\nwant it to end with\n%s", got, want)
+	}
+}
+
+func TestOutputName(t *testing.T) {
+	tests := []struct {
+		name      string
+		goPackage string
+		pkgPath   string
+		want      string
+	}{
+		{name: "directory", pkgPath: "../testdata/embedded", want: "embedded"},
+		{name: "current directory", pkgPath: ".", want: "generator"},
+		{name: "go generate", goPackage: "mypkg", pkgPath: ".", want: "mypkg"},
+		{name: "go generate with --pkg-path", goPackage: "mypkg", pkgPath: "../testdata/embedded", want: "embedded"},
+		{name: "invalid directory name", pkgPath: "../testdata/123-foo", want: "pkg123_foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOPACKAGE", tt.goPackage)
+			if got := outputName(tt.pkgPath, nil); got != tt.want {
+				t.Errorf("outputName(%q) = %q, want %q", tt.pkgPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateNamesOutputAfterPackage(t *testing.T) {
+	files := generateFiles(t, Config{PkgPath: "../testdata/versioned/v2"})
+
+	got, ok := files["versioned_functypes.go"]
+	if !ok {
+		t.Fatalf("Generate() wrote %v, want versioned_functypes.go", files)
+	}
+	if want := "type Publish func(topic string, payload []byte) error\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("versioned_functypes.go =\n%s\nwant it to end with\n%s", got, want)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name        string
+		want        string
+		wantWarning bool
+	}{
+		{name: "foo", want: "foo"},
+		{name: "foo_bar2", want: "foo_bar2"},
+		{name: "123-foo", want: "pkg123_foo", wantWarning: true},
+		{name: "my pkg.v2", want: "my_pkg_v2", wantWarning: true},
+		{name: "løype", want: "løype"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged := warnings(t)
+			if got := sanitizeName(tt.name); got != tt.want {
+				t.Errorf("sanitizeName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+			if warned := len(logged()) > 0; warned != tt.wantWarning {
+				t.Errorf("sanitizeName(%q) warnings = %q, want a warning: %v", tt.name, logged(), tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// goListInput is where --from-go-list reads the output of go list -json from. It's a variable so tests can feed it synthetic output.
+var goListInput io.Reader = os.Stdin
+
+// goListPackage holds the fields of a package in the output of go list -json which --from-go-list uses.
+type goListPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+}
+
+// readGoList reads the stream of JSON objects printed by go list -json, like go list -json ./..., and returns the directories of the listed packages to scan.
+// Packages without .go files for the current build, like a directory with only tests, are left out since there's nothing to load from them.
+func readGoList(r io.Reader) ([]string, error) {
+	var dirs []string
+	decoder := json.NewDecoder(r)
+	for {
+		var pkg goListPackage
+		err := decoder.Decode(&pkg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("--from-go-list: failed to read the go list -json output: %v", err)
+		}
+
+		if pkg.Dir == "" {
+			return nil, fmt.Errorf("--from-go-list: the package %s has no Dir, is the input the output of go list -json?", pkg.ImportPath)
+		}
+		if len(pkg.GoFiles) == 0 {
+			logrus.Debugf("skipping %s because it has no .go files to load", pkg.ImportPath)
+			continue
+		}
+		dirs = append(dirs, pkg.Dir)
+	}
+
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("--from-go-list: the go list -json output lists no packages with .go files")
+	}
+	return dirs, nil
+}
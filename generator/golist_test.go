@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestReadGoList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "packages with .go files",
+			input: `{"ImportPath": "example.com/a", "Dir": "/src/a", "GoFiles": ["a.go"]}` + "\n" + `{"ImportPath": "example.com/b", "Dir": "/src/b", "GoFiles": ["b.go", "c.go"], "Imports": ["fmt"]}`,
+			want:  []string{"/src/a", "/src/b"},
+		},
+		{
+			name:  "packages without .go files are left out",
+			input: `{"ImportPath": "example.com/a", "Dir": "/src/a", "GoFiles": ["a.go"]}{"ImportPath": "example.com/tests", "Dir": "/src/tests", "TestGoFiles": ["a_test.go"]}`,
+			want:  []string{"/src/a"},
+		},
+		{
+			name:    "no packages with .go files",
+			input:   `{"ImportPath": "example.com/tests", "Dir": "/src/tests"}`,
+			wantErr: "--from-go-list: the go list -json output lists no packages with .go files",
+		},
+		{
+			name:    "empty input",
+			wantErr: "--from-go-list: the go list -json output lists no packages with .go files",
+		},
+		{
+			name:    "packages without a directory",
+			input:   `{"ImportPath": "example.com/a", "GoFiles": ["a.go"]}`,
+			wantErr: "--from-go-list: the package example.com/a has no Dir, is the input the output of go list -json?",
+		},
+		{
+			name:    "not JSON",
+			input:   "example.com/a\nexample.com/b\n",
+			wantErr: "--from-go-list: failed to read the go list -json output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readGoList(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("readGoList() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readGoList() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readGoList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFromGoList(t *testing.T) {
+	var input strings.Builder
+	for _, dir := range []string{"../testdata/blanks", "../testdata/green"} {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input.WriteString(`{"Dir": "` + abs + `", "GoFiles": ["x.go"]}` + "\n")
+	}
+
+	in := goListInput
+	goListInput = strings.NewReader(input.String())
+	t.Cleanup(func() { goListInput = in })
+
+	outDir := t.TempDir()
+	files, err := Render(Config{FromGoList: true, OutDir: outDir})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var got []string
+	for outFilePath := range files {
+		got = append(got, strings.TrimPrefix(outFilePath, outDir+"/"))
+	}
+	sort.Strings(got)
+	// Only the listed packages are scanned, not the purple package below green.
+	want := []string{"blanks_functypes.go", "green_functypes.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Render() rendered %v, want %v", got, want)
+	}
+}
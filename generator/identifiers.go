@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkIdentifiers is a safety net across all the emit options: it checks that every identifier declared by the rendered Go files is a valid identifier, and that it's unique within its scope. That's the package for top-level declarations, the struct for fields and the signature for parameters and results.
+// Violations are reported together, since a bug producing a bad name tends to produce many of them. Unlike --validate it doesn't type check, so it's cheap enough to always run.
+func (g *generator) checkIdentifiers() error {
+	byDir := make(map[string][]string)
+	for outFilePath := range g.files {
+		if filepath.Ext(outFilePath) == ".go" {
+			dir := filepath.Dir(outFilePath)
+			byDir[dir] = append(byDir[dir], outFilePath)
+		}
+	}
+
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var violations []string
+	duplicateTopLevel := false
+	for _, dir := range dirs {
+		paths := byDir[dir]
+		sort.Strings(paths)
+		check := g.checkIdentifiersOf(paths)
+		violations = append(violations, check.violations...)
+		duplicateTopLevel = duplicateTopLevel || check.duplicateTopLevel
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("the generated code declares %d invalid or duplicate identifiers:\n\t%s", len(violations), strings.Join(violations, "\n\t"))
+	// The generated declarations are named after the methods, so a method can be named like the code generated for another one, like a MustRead method next to the Must wrapper of Read. That isn't a bug but needs narrowing down.
+	if duplicateTopLevel && g.nameTemplate != nil {
+		err = fmt.Errorf("%w\nthe function types are named by --name-template, make it render different names for them, like with {{.Interface}}, or use --include, --exclude or --exclude-methods to convert only one of them", err)
+	} else if duplicateTopLevel {
+		err = fmt.Errorf("%w\nthe generated declarations are named after the methods they're generated for, use --include, --exclude, --exclude-methods or --aggregate-mode to convert only one of them, or --route or --subpackages to write them to different packages", err)
+	}
+	return err
+}
+
+// checkIdentifiersOf checks the identifiers of the rendered files, which make up one package.
+func (g *generator) checkIdentifiersOf(paths []string) *identifierCheck {
+	fset := token.NewFileSet()
+	check := &identifierCheck{fset: fset, topLevel: make(map[string]token.Pos)}
+
+	for _, outFilePath := range paths {
+		file, err := parser.ParseFile(fset, outFilePath, g.files[outFilePath], parser.SkipObjectResolution)
+		if err != nil {
+			check.violations = append(check.violations, fmt.Sprintf("%s: %v", outFilePath, err))
+			continue
+		}
+
+		// Test files are part of the same package, except for the ones of an external _test package.
+		if strings.HasSuffix(file.Name.Name, "_test") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			check.decl(decl)
+		}
+	}
+	return check
+}
+
+// identifierCheck collects the identifier violations of the files of a package, see checkIdentifiers.
+type identifierCheck struct {
+	fset       *token.FileSet
+	topLevel   map[string]token.Pos
+	violations []string
+	// duplicateTopLevel is set when a top-level declaration is declared more than once.
+	duplicateTopLevel bool
+}
+
+// declare checks the identifier declared in the scope, recording it there by its name.
+func (c *identifierCheck) declare(ident *ast.Ident, scope map[string]token.Pos, what string) {
+	if ident != nil {
+		c.declareAs(ident, ident.Name, scope, what)
+	}
+}
+
+// declareTopLevel checks the identifier declared at the top level of the package, recording it by the given key. That's the name, except for methods.
+func (c *identifierCheck) declareTopLevel(ident *ast.Ident, key, what string) {
+	// A package can have many init functions.
+	if key == "init" && what == "function" {
+		return
+	}
+	if c.declareAs(ident, key, c.topLevel, what) {
+		c.duplicateTopLevel = true
+	}
+}
+
+// declareAs checks the identifier declared in the scope, recording it there by the given key. It returns true if the key is declared already.
+func (c *identifierCheck) declareAs(ident *ast.Ident, key string, scope map[string]token.Pos, what string) bool {
+	if ident.Name == "_" {
+		return false
+	}
+	position := c.fset.Position(ident.Pos())
+	if !token.IsIdentifier(ident.Name) {
+		c.violations = append(c.violations, fmt.Sprintf("%s: %s %q is not a valid identifier", position, what, ident.Name))
+		return false
+	}
+	if first, ok := scope[key]; ok {
+		c.violations = append(c.violations, fmt.Sprintf("%s: %s %s is declared already at %s", position, what, key, c.fset.Position(first)))
+		return true
+	}
+	scope[key] = ident.Pos()
+	return false
+}
+
+// decl checks the identifiers declared by a top-level declaration and the types in it.
+func (c *identifierCheck) decl(decl ast.Decl) {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		params := make(map[string]token.Pos)
+		if decl.Recv != nil {
+			c.fields(decl.Recv, params, "receiver")
+			// Methods are scoped to their receiver type, like <Receiver>.<Method>.
+			c.declareTopLevel(decl.Name, funcDeclName(decl), "method")
+		} else {
+			c.declareTopLevel(decl.Name, decl.Name.Name, "function")
+		}
+		c.signature(decl.Type, params)
+		if decl.Body != nil {
+			ast.Inspect(decl.Body, c.inspectTypes)
+		}
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				c.declareTopLevel(spec.Name, spec.Name.Name, "type")
+				if spec.TypeParams != nil {
+					c.fields(spec.TypeParams, make(map[string]token.Pos), "type parameter")
+				}
+				ast.Inspect(spec.Type, c.inspectTypes)
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					c.declareTopLevel(name, name.Name, decl.Tok.String())
+				}
+				for _, value := range spec.Values {
+					ast.Inspect(value, c.inspectTypes)
+				}
+			}
+		}
+	}
+}
+
+// inspectTypes checks the struct fields and signatures of the types found while inspecting a node. It matches the signature of ast.Inspect.
+func (c *identifierCheck) inspectTypes(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.StructType:
+		c.fields(node.Fields, make(map[string]token.Pos), "field")
+	case *ast.FuncType:
+		c.signature(node, make(map[string]token.Pos))
+		return false
+	}
+	return true
+}
+
+// signature checks the type parameters, parameters and results of a signature, which share one scope.
+func (c *identifierCheck) signature(funcType *ast.FuncType, scope map[string]token.Pos) {
+	if funcType.TypeParams != nil {
+		c.fields(funcType.TypeParams, scope, "type parameter")
+	}
+	c.fields(funcType.Params, scope, "parameter")
+	if funcType.Results != nil {
+		c.fields(funcType.Results, scope, "result")
+	}
+}
+
+// fields checks the names in the field list, and the types of the fields.
+func (c *identifierCheck) fields(list *ast.FieldList, scope map[string]token.Pos, what string) {
+	if list == nil {
+		return
+	}
+	for _, field := range list.List {
+		for _, name := range field.Names {
+			c.declare(name, scope, what)
+		}
+		ast.Inspect(field.Type, c.inspectTypes)
+	}
+}
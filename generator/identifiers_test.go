@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckIdentifiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"out/a_functypes.go":      "package functypes\n\ntype Read func(p []byte) (n int, err error)\n\nfunc init() {}\n",
+				"out/b_functypes.go":      "package functypes\n\ntype ReaderFuncs struct {\n\tReadFunc Read\n}\n\nfunc (a *ReaderFuncs) Read(p []byte) (int, error) {\n\treturn a.ReadFunc(p)\n}\n\nfunc init() {}\n",
+				"other/a_functypes.go":    "package functypes\n\ntype Read func()\n",
+				"out/a_functypes_test.go": "package functypes_test\n\ntype Read func()\n",
+			},
+		},
+		{
+			name: "duplicates in each scope",
+			files: map[string]string{
+				"out/a_functypes.go": "package functypes\n\ntype Read func(p []byte, p int) (p bool)\n\ntype ReaderFuncs struct {\n\tReadFunc Read\n\tReadFunc Read\n}\n",
+				"out/b_functypes.go": "package functypes\n\nvar Read = 1\n",
+			},
+			want: "the generated code declares 4 invalid or duplicate identifiers:\n" +
+				"\tout/a_functypes.go:3:26: parameter p is declared already at out/a_functypes.go:3:16\n" +
+				"\tout/a_functypes.go:3:34: result p is declared already at out/a_functypes.go:3:16\n" +
+				"\tout/a_functypes.go:7:2: field ReadFunc is declared already at out/a_functypes.go:6:2\n" +
+				"\tout/b_functypes.go:3:5: var Read is declared already at out/a_functypes.go:3:6\n" +
+				"the generated declarations are named after the methods they're generated for",
+		},
+		{
+			name: "unparsable",
+			files: map[string]string{
+				"out/a_functypes.go": "package functypes\n\ntype Not Valid func()\n",
+			},
+			want: "the generated code declares 1 invalid or duplicate identifiers:\n\tout/a_functypes.go: out/a_functypes.go:3:16: expected ';', found 'func'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := newGenerator(Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for path, content := range tt.files {
+				g.files[path] = []byte(content)
+			}
+
+			err = g.checkIdentifiers()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("checkIdentifiers() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("checkIdentifiers() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
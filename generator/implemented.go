@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// concreteTypes returns the named non-interface types declared at package level in the packages, which --only-implemented checks the interfaces against.
+// Generic types are left out, since whether they implement an interface depends on how they're instantiated.
+func concreteTypes(pkgs []*packages.Package) []*types.Named {
+	var named []*types.Named
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		for _, obj := range sortedObjects(pkg.Types.Scope()) {
+			typeName, ok := obj.(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			t, ok := typeName.Type().(*types.Named)
+			if !ok || t.TypeParams().Len() > 0 || types.IsInterface(t) {
+				continue
+			}
+			named = append(named, t)
+		}
+	}
+	return named
+}
+
+// implementer returns the first of the concrete types implementing the interface, directly or through a pointer to it, or nil if none of them do.
+func (g *generator) implementer(iface *types.Interface) *types.Named {
+	for _, t := range g.concreteTypes {
+		if types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface) {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package generator
+
+import "testing"
+
+func TestGenerateOnlyImplemented(t *testing.T) {
+	tests := []struct {
+		name            string
+		onlyImplemented bool
+		want            string
+	}{
+		{
+			name: "all interfaces",
+			want: "type Archive func(key string) error\ntype Notify func(message string)\ntype Get func(key string) ([]byte, error)\n",
+		},
+		{
+			// Archiver isn't implemented by any of the scanned types, while Notifier is implemented through a pointer receiver.
+			name:            "only implemented",
+			onlyImplemented: true,
+			want:            "type Notify func(message string)\ntype Get func(key string) ([]byte, error)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/implemented/...", OnlyImplemented: tt.onlyImplemented})["implemented_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// importSet collects the packages referenced by the generated code, so that their types can be qualified and the file can import them.
+// Each package is imported under its package name, unless that name is already taken by another package (or reserved), in which case it gets a numbered alias like v12. Major versions of a module, like foo and foo/v2, get the major version as suffix instead, like foov2.
+type importSet struct {
+	// imports maps import paths to the imports referenced so far.
+	imports map[string]*importEntry
+	// taken are the names that can't be used for new imports: names already in use and reserved names.
+	taken map[string]bool
+	// local is the import path of the package the generated file lives in, whose types are referenced without qualifier or import (see --qualify-relative-to). Empty for the generated functypes package.
+	local string
+	// qualified records the packages qualified while it's not nil, by import path. Used by --explain to tell which packages a declaration references.
+	qualified map[string]*importEntry
+}
+
+// importEntry is a single package referenced by the generated code.
+type importEntry struct {
+	// path is the package's import path.
+	path string
+	// pkgName is the package's declared name.
+	pkgName string
+	// name is the name used to qualify the package's types in the generated code. It differs from pkgName if the package needs an alias.
+	name string
+	// explicit is set for packages whose declared name isn't known, which are imported with name as an explicit alias even if it's the same as pkgName. See markExplicit.
+	explicit bool
+}
+
+// newImportSet returns an empty importSet. The reserved names will never be used as import names, for example because the generated file already imports a package under that name.
+func newImportSet(reserved ...string) *importSet {
+	s := &importSet{
+		imports: make(map[string]*importEntry),
+		taken:   make(map[string]bool),
+	}
+	for _, name := range reserved {
+		s.taken[name] = true
+	}
+	return s
+}
+
+// qualify returns the name to qualify the package's types with, adding the package to the set of imports the first time it's referenced.
+// The types of the local package aren't qualified, in which case it returns an empty string. It matches the signature of types.Qualifier.
+func (s *importSet) qualify(pkg *types.Package) string {
+	if s.local != "" && pkg.Path() == s.local {
+		return ""
+	}
+
+	entry, ok := s.imports[pkg.Path()]
+	if !ok {
+		name := pkg.Name()
+		if major := majorVersion(pkg.Path()); major != "" && s.taken[name] && !s.taken[name+major] {
+			name += major
+		}
+		name = uniqueName(name, s.taken)
+		s.taken[name] = true
+		entry = &importEntry{path: pkg.Path(), pkgName: pkg.Name(), name: name}
+		s.imports[pkg.Path()] = entry
+	}
+
+	if s.qualified != nil {
+		s.qualified[pkg.Path()] = entry
+	}
+	return entry.name
+}
+
+// majorVersion returns the major version suffix of the import path, like v2 for example.com/foo/v2 and gopkg.in/yaml.v3, or an empty string if the path has none.
+func majorVersion(path string) string {
+	elem := path[strings.LastIndex(path, "/")+1:]
+	if dot := strings.LastIndex(elem, "."); dot >= 0 && strings.HasPrefix(path, "gopkg.in/") {
+		elem = elem[dot+1:]
+	}
+	if len(elem) < 2 || elem[0] != 'v' || elem[1] < '1' || elem[1] > '9' {
+		return ""
+	}
+	for _, r := range elem[2:] {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return elem
+}
+
+// qualifiedName returns the name of an object declared in the package, qualified unless the package is local, like io.Reader.
+func (s *importSet) qualifiedName(pkg *types.Package, name string) string {
+	if qualifier := s.qualify(pkg); qualifier != "" {
+		return qualifier + "." + name
+	}
+	return name
+}
+
+// assignInOrder adds the packages referenced by the other set, in import path order, so that packages with the same name get their numbered aliases in that order: the first x keeps its name, and the next ones become x2, x3 and so on.
+func (s *importSet) assignInOrder(other *importSet) {
+	paths := make([]string, 0, len(other.imports))
+	for path := range other.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		s.qualify(types.NewPackage(path, other.imports[path].pkgName))
+		if other.imports[path].explicit {
+			s.markExplicit(path)
+		}
+	}
+}
+
+// markExplicit makes the package with the import path, which must have been qualified already, be imported with an explicit alias. It's used for the stand-ins of packages which failed to load with --ast-fallback, whose pkgName is only what the source refers to them by.
+func (s *importSet) markExplicit(path string) {
+	if entry, ok := s.imports[path]; ok {
+		entry.explicit = true
+	}
+}
+
+// add adds the package to the set of imports under the given name. Used for imports the generated code needs regardless of the rendered types, like the testing package in generated tests.
+func (s *importSet) add(path, name string) {
+	s.imports[path] = &importEntry{path: path, pkgName: name, name: name}
+	s.taken[name] = true
+}
+
+// block returns the import declaration for all referenced packages, sorted by import path, or an empty string if no packages were referenced.
+func (s *importSet) block() string {
+	if len(s.imports) == 0 {
+		return ""
+	}
+
+	paths := make([]string, 0, len(s.imports))
+	for path := range s.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	builder := &strings.Builder{}
+	builder.WriteString("import (\n")
+	for _, path := range paths {
+		entry := s.imports[path]
+		if entry.name == entry.pkgName && !entry.explicit {
+			builder.WriteString(fmt.Sprintf("\t%q\n", entry.path))
+		} else {
+			builder.WriteString(fmt.Sprintf("\t%s %q\n", entry.name, entry.path))
+		}
+	}
+	builder.WriteString(")\n")
+	return builder.String()
+}
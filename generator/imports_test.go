@@ -0,0 +1,389 @@
+package generator
+
+import (
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateGenericInstantiationsBehindPointers(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/generics"})["generics_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/cache"
+)
+
+type Nested func(c *cache.Cache[string, *cache.Entry[[]byte]]) []*cache.Entry[int]
+type Use func(c *cache.Cache[string, int]) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateNumbersAliasesByImportPath(t *testing.T) {
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	x3 "github.com/eaardal/functypes/testdata/deep/gamma/pkg/platform/storage/x"
+)
+
+type A func(id x3.ID) x2.ID
+type B func(id x.ID) x3.ID
+`
+	for run := 1; run <= 3; run++ {
+		got := generateFiles(t, Config{PkgPath: "../testdata/deep/lookup"})["lookup_functypes.go"]
+		if got != want {
+			t.Fatalf("Generate() run %d =\n%s\nwant\n%s", run, got, want)
+		}
+	}
+}
+
+func TestGenerateBuiltinErrorNextToCustomError(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/domainerr", Include: "^Syncer$"})["domainerr_functypes.go"]
+
+	// Only the custom error type is qualified and imported, the builtin error never is.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/domainerr"
+)
+
+type Sync func(key string) (conflict *domainerr.Error, err error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateCrossPackageParams(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/handlers"})["handlers_functypes.go"]
+
+	// The package's own Request is qualified as well, since the generated file is in another package.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"context"
+	"github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	"github.com/eaardal/functypes/testdata/handlers"
+	"net/http"
+)
+
+type Copy func(from x.ID, to x2.ID) error
+type Handle func(ctx context.Context, r *http.Request) error
+type Route func(req handlers.Request) (string, bool)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	goTestGenerated(t, Config{PkgPath: "../testdata/handlers"}, nil)
+}
+
+func TestGenerateQualifyRelativeTo(t *testing.T) {
+	tests := []struct {
+		name              string
+		qualifyRelativeTo string
+		want              string
+	}{
+		{
+			name:              "the scanned package",
+			qualifyRelativeTo: "github.com/eaardal/functypes/testdata/handlers",
+			want: `package handlers
+
+import (
+	"context"
+	"github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	"net/http"
+)
+
+type Copy func(from x.ID, to x2.ID) error
+type Handle func(ctx context.Context, r *http.Request) error
+type Route func(req Request) (string, bool)
+`,
+		},
+		{
+			name:              "a referenced package",
+			qualifyRelativeTo: "github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x",
+			want: `package x
+
+import (
+	"context"
+	"github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	"github.com/eaardal/functypes/testdata/handlers"
+	"net/http"
+)
+
+type Copy func(from ID, to x.ID) error
+type Handle func(ctx context.Context, r *http.Request) error
+type Route func(req handlers.Request) (string, bool)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/handlers", QualifyRelativeTo: tt.qualifyRelativeTo})["handlers_functypes.go"]
+
+			want := generatedHeader + "\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestRenderImportsOnly(t *testing.T) {
+	outDir := t.TempDir()
+	files, err := Render(Config{PkgPath: "../testdata/handlers", OutDir: outDir, ImportsOnly: true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := map[string][]byte{
+		filepath.Join(outDir, "handlers_functypes.go"): []byte(`import (
+	"context"
+	"github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	"github.com/eaardal/functypes/testdata/handlers"
+	"net/http"
+)
+`),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Render() = %q, want %q", files, want)
+	}
+}
+
+func TestGenerateImportsOnly(t *testing.T) {
+	err := Generate(Config{PkgPath: "../testdata/handlers", OutDir: t.TempDir(), ImportsOnly: true})
+	if want := "--imports-only renders the import blocks instead of files and can't be written, use Render"; err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateAliasesImportsCollidingWithLocalIdentifiers(t *testing.T) {
+	tests := []struct {
+		name              string
+		qualifyRelativeTo string
+		want              string
+	}{
+		{
+			name: "functypes package",
+			want: "package functypes\n\nimport (\n\t\"context\"\n)\n\ntype Handle func(ctx context.Context, name string) error\ntype Lookup func(key string) string\n",
+		},
+		{
+			// The file shares its scope with localctx's own context interface.
+			name:              "the package declaring context",
+			qualifyRelativeTo: "github.com/eaardal/functypes/testdata/localctx",
+			want:              "package localctx\n\nimport (\n\tcontext2 \"context\"\n)\n\ntype Handle func(ctx context2.Context, name string) error\ntype Lookup func(key string) string\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/localctx", QualifyRelativeTo: tt.qualifyRelativeTo})["localctx_functypes.go"]
+
+			want := generatedHeader + "\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateMajorVersions(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/majors"})["majors_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/majors/foo"
+	foov2 "github.com/eaardal/functypes/testdata/majors/foo/v2"
+)
+
+type Migrate func(from foo.Schema) (foov2.Schema, error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	goTestGenerated(t, Config{PkgPath: "../testdata/majors"}, nil)
+}
+
+func TestImportSetQualify(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserved []string
+		local    string
+		// pkgs are qualified in order, as import path and package name.
+		pkgs [][2]string
+		want []string
+		// wantBlock is the import block written for the packages.
+		wantBlock string
+	}{
+		{
+			name:      "packages are imported by their name",
+			pkgs:      [][2]string{{"context", "context"}, {"net/http", "http"}},
+			want:      []string{"context", "http"},
+			wantBlock: "import (\n\t\"context\"\n\t\"net/http\"\n)\n",
+		},
+		{
+			name:      "packages sharing a name get numbered aliases",
+			pkgs:      [][2]string{{"example.com/a/x", "x"}, {"example.com/b/x", "x"}, {"example.com/c/x", "x"}, {"example.com/a/x", "x"}},
+			want:      []string{"x", "x2", "x3", "x"},
+			wantBlock: "import (\n\t\"example.com/a/x\"\n\tx2 \"example.com/b/x\"\n\tx3 \"example.com/c/x\"\n)\n",
+		},
+		{
+			name:      "major versions get the version as suffix",
+			pkgs:      [][2]string{{"example.com/foo", "foo"}, {"example.com/foo/v2", "foo"}},
+			want:      []string{"foo", "foov2"},
+			wantBlock: "import (\n\t\"example.com/foo\"\n\tfoov2 \"example.com/foo/v2\"\n)\n",
+		},
+		{
+			name:      "reserved names are never used",
+			reserved:  []string{"context"},
+			pkgs:      [][2]string{{"context", "context"}},
+			want:      []string{"context2"},
+			wantBlock: "import (\n\tcontext2 \"context\"\n)\n",
+		},
+		{
+			name:  "the local package isn't qualified or imported",
+			local: "example.com/app",
+			pkgs:  [][2]string{{"example.com/app", "app"}},
+			want:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newImportSet(tt.reserved...)
+			s.local = tt.local
+
+			var got []string
+			for _, pkg := range tt.pkgs {
+				got = append(got, s.qualify(types.NewPackage(pkg[0], pkg[1])))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("qualify() = %v, want %v", got, tt.want)
+			}
+			if block := s.block(); block != tt.wantBlock {
+				t.Errorf("block() =\n%s\nwant\n%s", block, tt.wantBlock)
+			}
+		})
+	}
+}
+
+func TestImportSetMarkExplicit(t *testing.T) {
+	s := newImportSet()
+	s.qualify(types.NewPackage("example.com/does/not/exist", "exist"))
+	s.markExplicit("example.com/does/not/exist")
+
+	// The explicit alias survives the passes the imports are assigned over.
+	next := newImportSet()
+	next.assignInOrder(s)
+
+	want := "import (\n\texist \"example.com/does/not/exist\"\n)\n"
+	if block := next.block(); block != want {
+		t.Errorf("block() =\n%s\nwant\n%s", block, want)
+	}
+}
+
+func TestRenderCrossPackageTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		outFile string
+		want    string
+	}{
+		{
+			name:    "standard library, packages sharing a name and the scanned package",
+			cfg:     Config{PkgPath: "../testdata/handlers"},
+			outFile: "handlers_functypes.go",
+			want: `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+import (
+	"context"
+	"github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	"github.com/eaardal/functypes/testdata/handlers"
+	"net/http"
+)
+
+type Copy func(from x.ID, to x2.ID) error
+type Handle func(ctx context.Context, r *http.Request) error
+type Route func(req handlers.Request) (string, bool)
+`,
+		},
+		{
+			name:    "aliases",
+			cfg:     Config{PkgPath: "../testdata/aliases"},
+			outFile: "aliases_functypes.go",
+			want: `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/aliases"
+)
+
+type Add func(delta aliases.MyInt) aliases.MyInt
+type Wait func(timeout aliases.Timeout) error
+type Keys func(set aliases.Set[string]) []string
+`,
+		},
+		{
+			name:    "expanded aliases",
+			cfg:     Config{PkgPath: "../testdata/aliases", ExpandAliases: true},
+			outFile: "aliases_functypes.go",
+			want: `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+import (
+	"time"
+)
+
+type Add func(delta int) int
+type Wait func(timeout time.Duration) error
+type Keys func(set map[string]bool) []string
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := t.TempDir()
+			tt.cfg.OutDir = outDir
+			// The generated code has to compile, not just look right.
+			tt.cfg.Validate = true
+
+			files, err := Render(tt.cfg)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := string(files[filepath.Join(outDir, tt.outFile)]); got != tt.want {
+				t.Errorf("Render() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// importsReportHeader is the comment on the first line of the --imports-report.
+const importsReportHeader = "# Packages referenced by the generated function types, with the number of function types referencing each."
+
+// referencedPackages renders a declaration with the given function and returns it along with the packages it references, by import path.
+func (r *renderer) referencedPackages(render func() string) (string, map[string]*importEntry) {
+	r.imports.qualified = make(map[string]*importEntry)
+	defer func() { r.imports.qualified = nil }()
+
+	rendered := render()
+	return rendered, r.imports.qualified
+}
+
+// countImports adds one to the count of each package referenced by a function type, for --imports-report.
+func (r *renderer) countImports(referenced map[string]*importEntry) {
+	for path := range referenced {
+		r.importCounts[path]++
+	}
+}
+
+// writeImportsReport writes the packages referenced by the generated function types to the --imports-report file, one <import path> <count> line per package.
+// Packages are listed by descending count, so the packages the generated code is most coupled to come first. Ties are listed in import path order.
+func (g *generator) writeImportsReport(reportPath string) error {
+	paths := make([]string, 0, len(g.importCounts))
+	for path := range g.importCounts {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if g.importCounts[paths[i]] != g.importCounts[paths[j]] {
+			return g.importCounts[paths[i]] > g.importCounts[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+
+	builder := &strings.Builder{}
+	builder.WriteString(importsReportHeader + "\n")
+	for _, path := range paths {
+		builder.WriteString(fmt.Sprintf("%s %d\n", path, g.importCounts[path]))
+	}
+
+	if err := os.WriteFile(reportPath, []byte(builder.String()), filePerm); err != nil {
+		return fmt.Errorf("write %s with perm %d: %w", reportPath, filePerm, err)
+	}
+	logrus.Infof("saved %s", reportPath)
+	return nil
+}
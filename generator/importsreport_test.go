@@ -0,0 +1,23 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateImportsReport(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "imports.txt")
+	generateFiles(t, Config{PkgPath: "../testdata/routes", ImportsReport: reportPath})
+
+	got, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Router's three methods all reference net/http, and only Wrap references time.
+	want := importsReportHeader + "\nnet/http 3\ntime 1\n"
+	if string(got) != want {
+		t.Errorf("imports report =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// parseInlinePackages parses an --inline-package value, which is a comma separated list of import paths, into a set.
+func parseInlinePackages(spec string) map[string]bool {
+	paths := make(map[string]bool)
+	for _, path := range strings.Split(spec, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths[path] = true
+		}
+	}
+	return paths
+}
+
+// inlines returns true if the named type is rendered by its bare name because its definition is copied into the generated file (see --inline-package), and records it for stringifyInlined.
+// Generic types are imported as usual, since their definition couldn't be copied without their type parameters.
+func (r *renderer) inlines(t *types.Named) bool {
+	obj := t.Obj()
+	if obj.Pkg() == nil || !r.inlinePackages[obj.Pkg().Path()] {
+		return false
+	}
+	if t.Origin().TypeParams().Len() > 0 {
+		if !r.notInlined[obj] {
+			r.notInlined[obj] = true
+			r.log.Warnf("importing %s.%s instead of inlining it because generic types can't be inlined", obj.Pkg().Path(), obj.Name())
+		}
+		return false
+	}
+
+	r.inlinedTypes = true
+	if !r.inlinedSeen[obj] {
+		r.inlinedSeen[obj] = true
+		r.inlined = append(r.inlined, t)
+	}
+	return true
+}
+
+// stringifyInlined will emit the definitions of the types inlined with --inline-package, as the type declaration of their underlying type.
+// Types referenced by the definitions are inlined as well if they're declared in an inlined package, so the definitions are emitted until there are no new ones. Their methods aren't copied, only the definition.
+// Fails if an inlined type has the name of a generated function type or another inlined type, since both would be declared in the same file.
+func (r *renderer) stringifyInlined() (string, error) {
+	declared := make(map[string]string)
+	for _, decl := range r.decls {
+		declared[decl.Name] = fmt.Sprintf("the function type of %s.%s", decl.Interface, decl.Name)
+	}
+
+	builder := &strings.Builder{}
+	for i := 0; i < len(r.inlined); i++ {
+		obj := r.inlined[i].Obj()
+		if other, ok := declared[obj.Name()]; ok {
+			return "", fmt.Errorf("--inline-package: can't inline %s.%s because the generated file declares %s with the same name", obj.Pkg().Path(), obj.Name(), other)
+		}
+		declared[obj.Name()] = fmt.Sprintf("the inlined %s.%s", obj.Pkg().Path(), obj.Name())
+
+		builder.WriteString(fmt.Sprintf("// %s is inlined from %s.\n", obj.Name(), obj.Pkg().Path()))
+		builder.WriteString(fmt.Sprintf("type %s %s\n", obj.Name(), r.typeString(r.inlined[i].Underlying())))
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,40 @@
+package generator
+
+import "testing"
+
+func TestGenerateInlinePackage(t *testing.T) {
+	cfg := Config{PkgPath: "../testdata/inline", InlinePackage: "github.com/eaardal/functypes/testdata/inline/model"}
+	got := generateFiles(t, cfg)["inline_functypes.go"]
+
+	// User's fields refer to ID and Tag, which are inlined along with it, while time isn't inlined and stays imported.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"time"
+)
+
+type GetUser func(id ID) (*User, error)
+type Tagged func(tag Tag) []User
+
+// ID is inlined from github.com/eaardal/functypes/testdata/inline/model.
+type ID string
+
+// User is inlined from github.com/eaardal/functypes/testdata/inline/model.
+type User struct {
+	ID      ID
+	Name    string
+	Created time.Time
+	Tags    []Tag
+}
+
+// Tag is inlined from github.com/eaardal/functypes/testdata/inline/model.
+type Tag string
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	goTestGenerated(t, cfg, nil)
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// checkInternalImports warns about each internal package the generated file imports which can't be imported from the output directory.
+// A package below an internal directory, like example.com/a/internal/b, can only be imported by the packages rooted at the internal directory's parent (example.com/a), so the generated file wouldn't compile outside of it.
+func checkInternalImports(pkgs []*packages.Package, outDir, outFilePath string, imports *importSet) {
+	outPkgPath, ok := outDirImportPath(pkgs, outDir)
+	if !ok {
+		return
+	}
+
+	for importPath := range imports.imports {
+		root, ok := internalRoot(importPath)
+		if !ok || outPkgPath == root || strings.HasPrefix(outPkgPath, root+"/") {
+			continue
+		}
+		logrus.Warnf("%s imports the internal package %s, which can only be imported from within %s: move --out-dir below %s for the generated code to compile", outFilePath, importPath, root, root)
+	}
+}
+
+// internalRoot returns the import path of the packages allowed to import the given internal package: the path up to the parent of its last internal element.
+// Returns false if the package isn't internal.
+func internalRoot(importPath string) (string, bool) {
+	elems := strings.Split(importPath, "/")
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i] == "internal" {
+			return strings.Join(elems[:i], "/"), true
+		}
+	}
+	return "", false
+}
+
+// outDirImportPath returns the import path the generated package in the output directory will have, derived from the directory and import path of a loaded package.
+// This assumes the output directory is in the same module as the package. Returns false if no loaded package has a directory to derive it from.
+func outDirImportPath(pkgs []*packages.Package, outDir string) (string, bool) {
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, pkg := range pkgs {
+		dir := packageDir(pkg)
+		if dir == "" {
+			continue
+		}
+
+		rel, err := filepath.Rel(dir, absOutDir)
+		if err != nil {
+			continue
+		}
+		return path.Join(pkg.PkgPath, filepath.ToSlash(rel)), true
+	}
+	return "", false
+}
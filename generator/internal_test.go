@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGenerateWarnsAboutInternalImports(t *testing.T) {
+	insideDir, err := os.MkdirTemp("../testdata", "internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(insideDir) })
+	outsideDir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		outDir string
+		want   []string
+	}{
+		{name: "within the boundary", outDir: insideDir},
+		{
+			name:   "outside the boundary",
+			outDir: outsideDir,
+			want: []string{
+				filepath.Join(outsideDir, "secret_functypes.go") + " imports the internal package github.com/eaardal/functypes/testdata/internal/secret, which can only be imported from within github.com/eaardal/functypes/testdata: move --out-dir below github.com/eaardal/functypes/testdata for the generated code to compile",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged := warnings(t)
+			generateFiles(t, Config{PkgPath: "../testdata/internal/secret", OutDir: tt.outDir})
+
+			if got := logged(); !slices.Equal(got, tt.want) {
+				t.Errorf("warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInternalRoot(t *testing.T) {
+	tests := []struct {
+		importPath string
+		want       string
+		wantOK     bool
+	}{
+		{importPath: "example.com/a/internal/b", want: "example.com/a", wantOK: true},
+		{importPath: "example.com/a/internal/b/internal/c", want: "example.com/a/internal/b", wantOK: true},
+		{importPath: "internal/bytealg", want: "", wantOK: true},
+		{importPath: "example.com/a/internals", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			got, ok := internalRoot(tt.importPath)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("internalRoot(%q) = %q, %v, want %q, %v", tt.importPath, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// generateGroups renders each group of packages sharing an output file (see groupByName), with up to --jobs groups at a time.
+// Every group is rendered by a fork of the generator, which collects the files and everything else rendered for it. The forks are merged in the order of the groups once all of them are done, so the output is the same for any number of jobs, and nothing is written to a file concurrently.
+func (g *generator) generateGroups(groups [][]*packages.Package) error {
+	forks := make([]*generator, len(groups))
+	errs := make([]error, len(groups))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(g.cfg.Jobs, len(groups)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				forks[i] = g.fork()
+				errs[i] = forks[i].generateRecovered(groups[i], groups[i][0].Name)
+			}
+		}()
+	}
+	for i := range groups {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	// The error of the first failing group is returned, like when the groups are rendered one after another.
+	for i, fork := range forks {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		g.merge(fork)
+	}
+	return nil
+}
+
+// generateRecovered is generate, returning a panic as an error like Generate does, which only recovers panics of its own goroutine.
+func (g *generator) generateRecovered(pkgs []*packages.Package, pkgName string) (err error) {
+	defer recoverPanic(&err)
+	return g.generate(pkgs, pkgName)
+}
+
+// fork returns a copy of the generator for rendering a group of packages alongside others, with its own state for what rendering writes to, see merge.
+// What the first pass of collidingMethods records is complete by then, so the forks only read it: the processed interfaces and the origins of the --method method.
+func (g *generator) fork() *generator {
+	fork := *g
+	fork.files = make(map[string][]byte)
+	fork.importCounts = make(map[string]int)
+	fork.locked = make(map[string]lockEntry)
+	fork.generated = nil
+	fork.processed = maps.Clone(g.processed)
+	fork.methodOrigins = slices.Clip(g.methodOrigins)
+	fork.methodCollisions = make(map[string]bool)
+	fork.generatedDecls = maps.Clone(g.generatedDecls)
+	return &fork
+}
+
+// merge adds what the fork rendered to the generator.
+func (g *generator) merge(fork *generator) {
+	maps.Copy(g.files, fork.files)
+	for path, count := range fork.importCounts {
+		g.importCounts[path] += count
+	}
+	maps.Copy(g.locked, fork.locked)
+	g.generated = append(g.generated, fork.generated...)
+	maps.Copy(g.methodCollisions, fork.methodCollisions)
+}
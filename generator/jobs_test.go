@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRenderJobs(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "function types", cfg: Config{PkgPath: "./..."}},
+		{name: "nil checks and examples", cfg: Config{PkgPath: "./...", EmitNilChecks: true, EmitExamples: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ./... doesn't match packages in testdata directories, unless run inside it. All fixtures are rendered then.
+			t.Chdir("../testdata")
+			tt.cfg.OutDir = t.TempDir()
+			tt.cfg.Jobs = 1
+			want, err := Render(tt.cfg)
+			if err != nil {
+				t.Fatalf("Render(-jobs=1) error = %v", err)
+			}
+
+			// The packages are rendered in another order with every run, which mustn't change the output.
+			for _, jobs := range []int{2, 8} {
+				tt.cfg.Jobs = jobs
+				for run := 0; run < 3; run++ {
+					got, err := Render(tt.cfg)
+					if err != nil {
+						t.Fatalf("Render(-jobs=%d) error = %v", jobs, err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("Render(-jobs=%d) differs from Render(-jobs=1):\n%s", jobs, diffFiles(got, want))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateJobsShareOutputFiles(t *testing.T) {
+	// a/util and b/util share util_functypes.go, which they're rendered into together by the same job.
+	files := generateFiles(t, Config{PkgPath: "../testdata/samename/...", Jobs: 4})
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"time"
+)
+
+type Hash func(b []byte) string
+type Now func() time.Time
+`
+	if got := files["util_functypes.go"]; got != want {
+		t.Errorf("util_functypes.go =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJobsIsValidated(t *testing.T) {
+	_, err := newGenerator(Config{Jobs: -1})
+	if want := "--jobs must be at least 1, got -1"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
+
+// diffFiles describes how the rendered files differ from the wanted ones.
+func diffFiles(got, want map[string][]byte) string {
+	var diff string
+	for path, content := range want {
+		if other, ok := got[path]; !ok {
+			diff += fmt.Sprintf("missing %s\n", path)
+		} else if string(other) != string(content) {
+			diff += fmt.Sprintf("%s =\n%s\nwant\n%s\n", path, other, content)
+		}
+	}
+	for path := range got {
+		if _, ok := want[path]; !ok {
+			diff += fmt.Sprintf("unexpected %s\n", path)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// renamedMethodAliases implements --keep-aliases for the interface: it returns the hashes of the converted methods and the aliases to record in the --lock file, and the deprecated aliases to emit after the interface's function types.
+// A method of the previous run's lock entry which is gone now is taken to be renamed if exactly one of the new methods has the same signature hash, so its old function type name becomes an alias of the new one. Aliases of earlier runs are kept as long as the method they point to is still converted.
+func (r *renderer) renamedMethodAliases(named *types.Named, converted []*types.Func) (map[string]string, map[string]string, string) {
+	methods := make(map[string]string)
+	for _, meth := range converted {
+		methods[meth.Name()] = methodHash(meth)
+	}
+
+	key := lockKey(named)
+	previous, ok := r.previousLock[key]
+	if !ok {
+		return methods, nil, ""
+	}
+	// Aliases of generic function types would need the type parameters too.
+	if named.TypeParams().Len() > 0 {
+		r.log.Debugf("not keeping aliases for the renamed methods of %s because it's generic", named.Obj().Name())
+		return methods, nil, ""
+	}
+
+	aliases := make(map[string]string)
+	for old, name := range previous.aliases {
+		if _, ok := methods[name]; ok {
+			if _, ok := methods[old]; !ok {
+				aliases[old] = name
+			}
+		}
+	}
+
+	for old, hash := range previous.methods {
+		if _, ok := methods[old]; ok {
+			continue
+		}
+
+		var candidates []string
+		for name, newHash := range methods {
+			if _, existed := previous.methods[name]; !existed && newHash == hash {
+				candidates = append(candidates, name)
+			}
+		}
+		sort.Strings(candidates)
+
+		switch len(candidates) {
+		case 0:
+			r.log.Debugf("%s.%s was removed or changed its signature, so there's no alias to keep for it", named.Obj().Name(), old)
+		case 1:
+			aliases[old] = candidates[0]
+		default:
+			r.log.Warnf("not keeping an alias for the renamed method %s.%s because it matches several new methods (%s)", named.Obj().Name(), old, strings.Join(candidates, ", "))
+		}
+	}
+
+	builder := &strings.Builder{}
+	for _, old := range sortedKeys(aliases) {
+		oldName, name := r.funcTypeName(named.Obj(), old), r.funcTypeName(named.Obj(), aliases[old])
+		builder.WriteString(fmt.Sprintf("// Deprecated: Use %s instead, %s.%s was renamed.\ntype %s = %s\n", name, named.Obj().Name(), old, oldName, name))
+		r.log.Infof("added deprecated alias: %s = %s", oldName, name)
+	}
+	if len(aliases) == 0 {
+		aliases = nil
+	}
+	return methods, aliases, builder.String()
+}
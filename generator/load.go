@@ -0,0 +1,363 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+var packagesCfg = &packages.Config{
+	Mode:       packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedTypesInfo | packages.NeedTypes,
+	Context:    nil,
+	Logf:       nil,
+	Dir:        "",
+	Env:        nil,
+	BuildFlags: nil,
+	Fset:       nil,
+	ParseFile:  nil,
+	Tests:      false,
+	Overlay:    nil,
+}
+
+// packagesLoad loads packages with go/packages. It's a variable so tests can replace it, for example to simulate a panic while loading.
+var packagesLoad = packages.Load
+
+// loadsManyPackages returns true if the run can load many packages, either through a package pattern or --from-go-list. Each package gets its own output file then.
+func (g *generator) loadsManyPackages() bool {
+	return isPackagePattern(g.cfg.PkgPath) || g.cfg.FromGoList
+}
+
+// isPackagePattern returns true if the given --pkg-path is a package pattern such as ./... rather than the path to a single package directory.
+func isPackagePattern(pkgPath string) bool {
+	return strings.HasSuffix(pkgPath, "...")
+}
+
+// loadPackages loads the package(s) found at the given --pkg-path, or the packages listed on stdin with --from-go-list.
+// A package pattern (like ./...) is passed straight to packages.Load and can match many packages. Any other path is treated as a single package directory, which is loaded through one of its .go files.
+// Packages without source files are loaded from their export data instead, see fillTypesFromExportData.
+func (g *generator) loadPackages(pkgPath string) ([]*packages.Package, error) {
+	if g.cfg.ExportFile != "" {
+		return loadExportFile(g.cfg.ExportFile, pkgPath)
+	}
+
+	cfg, err := g.packagesConfig()
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []*packages.Package
+
+	if g.cfg.FromGoList {
+		var dirs []string
+		if dirs, err = readGoList(goListInput); err != nil {
+			return nil, err
+		}
+		logrus.Debugf("loading the directories listed by go list: %v", dirs)
+		pkgs, err = packagesLoad(cfg, dirs...)
+	} else if isPackagePattern(pkgPath) {
+		if g.cfg.SeedFile != "" {
+			return nil, fmt.Errorf("--seed-file can't be used with the package pattern %s", pkgPath)
+		}
+		if pkgs, err = packagesLoad(cfg, pkgPath); err == nil && len(pkgs) == 0 {
+			return nil, noPackagesError(pkgPath)
+		}
+	} else {
+		var fileName string
+		if g.cfg.SeedFile != "" {
+			fileName, err = seedFileInDirectory(pkgPath, g.cfg.SeedFile)
+		} else {
+			fileName, err = firstGoFileInDirectory(pkgPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var filePath string
+		if filePath, err = filepath.Abs(path.Join(pkgPath, fileName)); err != nil {
+			return nil, fmt.Errorf("failed to resolve absolute path of %s: %v", pkgPath, err)
+		}
+		logrus.Debugf("filePath: %s", filePath)
+
+		// The package is loaded from its own directory, so it's resolved through the module containing it (with its replace directives) even when that's not the module of the working directory.
+		cfg.Dir = filepath.Dir(filePath)
+		pkgs, err = packagesLoad(cfg, "file="+filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillTypesFromExportData(pkgs); err != nil {
+		return nil, err
+	}
+	logIgnoredFiles(pkgs)
+	return pkgs, nil
+}
+
+// packagesConfig returns a copy of packagesCfg for the run, with the contents of the files replaced by the --overlay file, if any.
+func (g *generator) packagesConfig() (*packages.Config, error) {
+	cfg := *packagesCfg
+	if g.cfg.Overlay != "" {
+		overlay, err := readOverlay(g.cfg.Overlay)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Overlay = overlay
+	}
+	return &cfg, nil
+}
+
+// readOverlay reads a JSON file in the format of the -overlay flag of go build, like {"Replace": {"a.go": "b.go"}}, and returns the contents of each replacement file by the absolute path of the file it replaces.
+// go/packages parses the files itself rather than through the go command, so the overlay has to be passed as file contents rather than on to the go command. Entries deleting a file, with an empty replacement path, can't be expressed that way and fail.
+func readOverlay(file string) (map[string][]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --overlay %s: %v", file, err)
+	}
+	var parsed struct{ Replace map[string]string }
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse --overlay %s: %v", file, err)
+	}
+
+	overlay := make(map[string][]byte, len(parsed.Replace))
+	for from, to := range parsed.Replace {
+		if to == "" {
+			return nil, fmt.Errorf("--overlay %s deletes %s, which is not supported", file, from)
+		}
+		if overlay[absPath(from)], err = os.ReadFile(to); err != nil {
+			return nil, fmt.Errorf("failed to read the replacement of %s in --overlay %s: %v", from, file, err)
+		}
+	}
+	return overlay, nil
+}
+
+// absPath returns the absolute form of the path, or the path as is if it can't be resolved.
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
+
+// groupByName groups the packages by package name, in the order the names first appear.
+func groupByName(pkgs []*packages.Package) [][]*packages.Package {
+	var groups [][]*packages.Package
+	index := make(map[string]int)
+	for _, pkg := range pkgs {
+		i, ok := index[pkg.Name]
+		if !ok {
+			i = len(groups)
+			index[pkg.Name] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], pkg)
+	}
+	return groups
+}
+
+// logIgnoredFiles logs the files of each package excluded by their build constraints, so it's clear which variant of a package with version or platform specific files the function types are generated from.
+// Packages are loaded with the release tags of the current toolchain, so a file with //go:build go1.18 is used by any toolchain since Go 1.18.
+func logIgnoredFiles(pkgs []*packages.Package) {
+	for _, pkg := range pkgs {
+		if len(pkg.IgnoredFiles) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(pkg.IgnoredFiles))
+		for _, file := range pkg.IgnoredFiles {
+			names = append(names, filepath.Base(file))
+		}
+		logrus.Infof("%s: using %s, ignoring %s because their build constraints exclude them with the current toolchain", pkg.PkgPath, strings.Join(goFileNames(pkg), ", "), strings.Join(names, ", "))
+	}
+}
+
+// goFileNames returns the base names of the package's .go files.
+func goFileNames(pkg *packages.Package) []string {
+	names := make([]string, 0, len(pkg.GoFiles))
+	for _, file := range pkg.GoFiles {
+		names = append(names, filepath.Base(file))
+	}
+	return names
+}
+
+// excludeOutputDir removes the packages located in (or below) the output directory from the packages to process.
+// When --out-dir points somewhere inside the tree scanned by a pattern like ./..., the next run would otherwise pick up the generated package and we'd end up generating function types from the generated function types.
+// The package with the local import path is kept though: with --qualify-relative-to the output directory can be the directory of a scanned package, which the generated files are part of.
+func excludeOutputDir(pkgs []*packages.Package, outDir, local string) ([]*packages.Package, error) {
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path of %s: %v", outDir, err)
+	}
+	// A symlinked output directory is compared by the directory it points to, which is where the generated package ends up.
+	if resolved, err := filepath.EvalSymlinks(absOutDir); err == nil {
+		absOutDir = resolved
+	}
+
+	var kept []*packages.Package
+	for _, pkg := range pkgs {
+		if dir := packageDir(pkg); dir != "" && isInsideDir(dir, absOutDir) && pkg.PkgPath != local {
+			logrus.Debugf("skipping %s because it's inside the output directory %s", pkg.PkgPath, absOutDir)
+			continue
+		}
+		kept = append(kept, pkg)
+	}
+	return kept, nil
+}
+
+// checkImportComments warns about packages whose import comment (like package foo // import "example.com/foo") disagrees with the path go/packages resolved them by.
+// The generated code imports a package by its pkg.PkgPath, which is the canonical path the go command resolved honoring the module path and, in GOPATH mode, the import comment. For packages using a vanity import path the two agree, but a stale comment would otherwise silently be ignored.
+func checkImportComments(pkgs []*packages.Package) {
+	for _, pkg := range pkgs {
+		dir := packageDir(pkg)
+		if dir == "" {
+			continue
+		}
+
+		buildPkg, err := build.ImportDir(dir, build.ImportComment)
+		if err != nil || buildPkg.ImportComment == "" {
+			continue
+		}
+
+		if buildPkg.ImportComment != pkg.PkgPath {
+			logrus.Warnf("%s declares the import comment %q, but is imported as %q because that's the path the go command resolves it by", dir, buildPkg.ImportComment, pkg.PkgPath)
+		}
+	}
+}
+
+// packageDir returns the directory containing the package's .go files, or an empty string if the package has no files.
+func packageDir(pkg *packages.Package) string {
+	if len(pkg.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+// isInsideDir returns true if dir is the same directory as parent or one of its subdirectories. Both paths are expected to be absolute.
+func isInsideDir(dir, parent string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// seedFileInDirectory validates the --seed-file given for the directory and returns its name. It's used instead of firstGoFileInDirectory for directories where the first .go file causes a bad load, for example because of its build constraints.
+func seedFileInDirectory(dir, seedFile string) (string, error) {
+	if filepath.Ext(seedFile) != ".go" {
+		return "", fmt.Errorf("--seed-file %s is not a .go file", seedFile)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, seedFile))
+	if err != nil {
+		return "", fmt.Errorf("--seed-file %s not found in %s: %v", seedFile, dir, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("--seed-file %s in %s is a directory", seedFile, dir)
+	}
+	return seedFile, nil
+}
+
+// firstGoFileInDirectory returns the name of the first .go file it finds in the given directory path.
+// Because package.Load requires a .go file which it'll use to inspect that file's package, the name of any .go file in the given directory will do, so we just grab the first.
+// Files excluded by their build constraints with the current toolchain, like a foo_go118.go variant with //go:build !go1.18, are skipped since loading through them doesn't load the package the toolchain builds.
+func firstGoFileInDirectory(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read directory %s: %v", dir, err)
+	}
+
+	logrus.Debugf("found %d entries in directory %s", len(entries), dir)
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		if match, err := build.Default.MatchFile(dir, entry.Name()); err == nil && !match {
+			logrus.Debugf("skipping %s because its build constraints exclude it", entry.Name())
+			continue
+		}
+
+		return entry.Name(), nil
+	}
+
+	return "", noGoFilesError(dir)
+}
+
+// noPackagesError returns the error for a package pattern matching no packages, which packages.Load doesn't treat as one. A pattern which isn't relative or absolute, like deep/..., is matched against import paths rather than directories, so the error suggests its relative form.
+func noPackagesError(pattern string) error {
+	if !build.IsLocalImport(pattern) && !filepath.IsAbs(pattern) {
+		return fmt.Errorf("the package pattern %s matched no packages, patterns not starting with ./ or / match import paths (use --pkg-path ./%s to scan the directory)", pattern, pattern)
+	}
+	return fmt.Errorf("the package pattern %s matched no packages", pattern)
+}
+
+// maxSuggestedDirs is the number of subdirectories with .go files listed by noGoFilesError.
+const maxSuggestedDirs = 5
+
+// noGoFilesError returns the error for a directory without .go files to load. Pointing --pkg-path at a directory above the package is a common mistake, so the error lists the subdirectories which do have .go files, if any, and suggests the ./... pattern.
+func noGoFilesError(dir string) error {
+	// One more than listed tells whether there are more.
+	subDirs := goFileDirsBelow(dir, maxSuggestedDirs+1)
+	if len(subDirs) == 0 {
+		return fmt.Errorf("found no .go files in %s", dir)
+	}
+
+	listed := subDirs
+	if len(listed) > maxSuggestedDirs {
+		listed = listed[:maxSuggestedDirs]
+	}
+	suggestion := strings.Join(listed, ", ")
+	if len(subDirs) > len(listed) {
+		suggestion += " and more"
+	}
+	return fmt.Errorf("found no .go files in %s, did you mean one of its subdirectories? These contain .go files: %s (use --pkg-path %s to scan them all)", dir, suggestion, patternBelow(dir))
+}
+
+// patternBelow returns the package pattern matching the packages in and below the directory, like ./deep/... for deep.
+// A relative directory keeps its leading ./, since a pattern without it is matched against import paths rather than directories (see noPackagesError).
+func patternBelow(dir string) string {
+	dir = filepath.Clean(dir)
+	if !filepath.IsAbs(dir) && !build.IsLocalImport(filepath.ToSlash(dir)) {
+		dir = "." + string(filepath.Separator) + dir
+	}
+	return dir + string(filepath.Separator) + "..."
+}
+
+// goFileDirsBelow returns up to max subdirectories of dir containing .go files, in lexical order. Like the ./... pattern, it doesn't look into the directories the go command ignores, like .git, _examples, testdata and vendor, nor into other modules. It stops at the first max, so a --pkg-path above a large tree is answered quickly.
+func goFileDirsBelow(dir string, max int) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	_ = filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || p == dir {
+			return nil
+		}
+		if entry.IsDir() {
+			if name := entry.Name(); strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(entry.Name(), ".go") {
+			if parent := filepath.Dir(p); !seen[parent] {
+				seen[parent] = true
+				dirs = append(dirs, parent)
+				if len(dirs) == max {
+					return filepath.SkipAll
+				}
+			}
+		}
+		return nil
+	})
+	return dirs
+}
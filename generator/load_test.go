@@ -0,0 +1,454 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestExcludeOutputDir(t *testing.T) {
+	tests := []struct {
+		name   string
+		outDir string
+		local  string
+		want   []string
+	}{
+		{
+			name:   "output directory outside the scanned tree",
+			outDir: "functypes",
+			want:   []string{"green", "purple"},
+		},
+		{
+			name:   "output directory is a package of the scanned tree",
+			outDir: "../testdata/green/purple",
+			want:   []string{"green"},
+		},
+		{
+			name:   "output directory below a package of the scanned tree",
+			outDir: "../testdata/green/purple/functypes",
+			want:   []string{"green", "purple"},
+		},
+		{
+			name:   "output directory is the package the files are rendered as part of",
+			outDir: "../testdata/green/purple",
+			local:  "github.com/eaardal/functypes/testdata/green/purple",
+			want:   []string{"green", "purple"},
+		},
+	}
+
+	g, err := newGenerator(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := g.loadPackages("../testdata/green/...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, err := excludeOutputDir(pkgs, tt.outDir, tt.local)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, pkg := range kept {
+				got = append(got, pkg.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("excludeOutputDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateVanityImport(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/vanity"})["vanity_functypes.go"]
+
+	// The package is imported by the path it declares in its import comment, which is also the path it's resolved by.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/vanity"
+)
+
+type Issue func(subject string) (vanity.Token, error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCheckImportComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgPath string
+		// wantWarning is the expected warning, none if the import comment agrees with the resolved path.
+		wantWarning string
+	}{
+		{
+			name:    "import comment of the resolved path",
+			pkgPath: "../testdata/vanity",
+		},
+		{
+			name:        "stale import comment",
+			pkgPath:     "../testdata/vanity/stale",
+			wantWarning: `declares the import comment "example.com/stale", but is imported as "github.com/eaardal/functypes/testdata/vanity/stale" because that's the path the go command resolves it by`,
+		},
+	}
+
+	g, err := newGenerator(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkgs, err := g.loadPackages(tt.pkgPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			logged := warnings(t)
+
+			checkImportComments(pkgs)
+
+			warnings := logged()
+			if tt.wantWarning == "" {
+				if len(warnings) > 0 {
+					t.Errorf("checkImportComments() warned %q, want no warnings", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.HasSuffix(warnings[0], tt.wantWarning) {
+				t.Errorf("checkImportComments() warned %q, want %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestGenerateSeedFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		seedFile string
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "first .go file",
+			// a_ignored.go is excluded by its build constraint, so the package is loaded through seed.go instead.
+			want: generatedHeader + "\n\npackage functypes\n\ntype Now func() int64\n",
+		},
+		{
+			name:     "seed file",
+			seedFile: "seed.go",
+			want:     generatedHeader + "\n\npackage functypes\n\ntype Now func() int64\n",
+		},
+		{
+			name:     "not a .go file",
+			seedFile: "seed.txt",
+			wantErr:  "--seed-file seed.txt is not a .go file",
+		},
+		{
+			name:     "missing file",
+			seedFile: "missing.go",
+			wantErr:  "--seed-file missing.go not found in ../testdata/seed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{PkgPath: "../testdata/seed", OutDir: t.TempDir(), SeedFile: tt.seedFile}
+
+			err := Generate(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if got := readFiles(t, cfg.OutDir)["seed_functypes.go"]; got != tt.want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateVersionGatedPackage(t *testing.T) {
+	hook := test.NewLocal(logrus.StandardLogger())
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	// clock_before_go118.go sorts first, but it's excluded with the current toolchain, so Clock is generated from clock_go118.go.
+	got := generateFiles(t, Config{PkgPath: "../testdata/versions"})["versions_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"time"
+)
+
+type After func(d time.Duration) <-chan time.Time
+type Now func() time.Time
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantLog := "github.com/eaardal/functypes/testdata/versions: using clock_go118.go, ignoring clock_before_go118.go because their build constraints exclude them with the current toolchain"
+	logged := false
+	for _, entry := range hook.AllEntries() {
+		logged = logged || entry.Message == wantLog
+	}
+	if !logged {
+		t.Errorf("didn't log %q", wantLog)
+	}
+}
+
+func TestGenerateSuggestsSubdirectories(t *testing.T) {
+	many := t.TempDir()
+	for i := 1; i <= 7; i++ {
+		dir := filepath.Join(many, fmt.Sprintf("pkg%d", i))
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "pkg.go"), []byte(fmt.Sprintf("package pkg%d\n", i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Like the ./... pattern, the suggestions leave out the directories the go command ignores and other modules.
+	ignored := t.TempDir()
+	for _, dir := range []string{".hidden", "_examples", "testdata", "vendor/example.com/dep", "module", "pkg"} {
+		if err := os.MkdirAll(filepath.Join(ignored, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(ignored, dir, "pkg.go"), []byte("package pkg\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(ignored, "module", "go.mod"), []byte("module example.com/module\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	empty := t.TempDir()
+	var manyDirs []string
+	for i := 1; i <= maxSuggestedDirs; i++ {
+		manyDirs = append(manyDirs, filepath.Join(many, fmt.Sprintf("pkg%d", i)))
+	}
+
+	tests := []struct {
+		name    string
+		pkgPath string
+		want    string
+	}{
+		{
+			name:    "parent directory",
+			pkgPath: "../testdata/deep",
+			want:    "found no .go files in ../testdata/deep, did you mean one of its subdirectories? These contain .go files: ../testdata/deep/alpha/pkg/platform/storage/x, ../testdata/deep/beta/pkg/platform/storage/x, ../testdata/deep/gamma/pkg/platform/storage/x, ../testdata/deep/lookup (use --pkg-path ../testdata/deep/... to scan them all)",
+		},
+		{
+			name:    "more subdirectories than listed",
+			pkgPath: many,
+			want:    fmt.Sprintf("found no .go files in %s, did you mean one of its subdirectories? These contain .go files: %s and more (use --pkg-path %s/... to scan them all)", many, strings.Join(manyDirs, ", "), many),
+		},
+		{
+			name:    "ignored subdirectories",
+			pkgPath: ignored,
+			want:    fmt.Sprintf("found no .go files in %s, did you mean one of its subdirectories? These contain .go files: %s (use --pkg-path %s/... to scan them all)", ignored, filepath.Join(ignored, "pkg"), ignored),
+		},
+		{
+			name:    "no subdirectories with .go files",
+			pkgPath: empty,
+			want:    "found no .go files in " + empty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Generate(Config{PkgPath: tt.pkgPath, OutDir: t.TempDir()})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternBelow(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: "deep", want: "./deep/..."},
+		{dir: "./deep/", want: "./deep/..."},
+		{dir: "../deep", want: "../deep/..."},
+		{dir: "/src/deep", want: "/src/deep/..."},
+	}
+
+	for _, tt := range tests {
+		if got := patternBelow(filepath.FromSlash(tt.dir)); got != filepath.FromSlash(tt.want) {
+			t.Errorf("patternBelow(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSharesOutputFileOfPackagesWithTheSameName(t *testing.T) {
+	files := generateFiles(t, Config{PkgPath: "../testdata/samename/..."})
+	delete(files, manifestFileName)
+
+	want := map[string]string{
+		"util_functypes.go": generatedHeader + `
+
+package functypes
+
+import (
+	"time"
+)
+
+type Hash func(b []byte) string
+type Now func() time.Time
+`,
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Generate() = %v, want %v", files, want)
+	}
+}
+
+func TestGroupByName(t *testing.T) {
+	pkgs := []*packages.Package{
+		{PkgPath: "a/util", Name: "util"},
+		{PkgPath: "a/http", Name: "http"},
+		{PkgPath: "b/util", Name: "util"},
+	}
+
+	var got [][]string
+	for _, group := range groupByName(pkgs) {
+		var paths []string
+		for _, pkg := range group {
+			paths = append(paths, pkg.PkgPath)
+		}
+		got = append(got, paths)
+	}
+
+	want := [][]string{{"a/util", "b/util"}, {"a/http"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByName() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateAgainstReplacedDependency(t *testing.T) {
+	// testdata/forked replaces example.com/upstream with its ./fork directory.
+	got := generateFiles(t, Config{PkgPath: "../testdata/forked/app"})["app_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"example.com/upstream"
+)
+
+type Publish func(event upstream.Event) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The types come from the fork, which declares the Forked field.
+	g, err := newGenerator(Config{PkgPath: "../testdata/forked/app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := g.loadPackages("../testdata/forked/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	imports := pkgs[0].Types.Imports()
+	if len(imports) != 1 || imports[0].Path() != "example.com/upstream" {
+		t.Fatalf("imports of %s = %v, want example.com/upstream", pkgs[0].PkgPath, imports)
+	}
+	event := imports[0].Scope().Lookup("Event").Type().Underlying().(*types.Struct)
+	if event.NumFields() != 2 || event.Field(1).Name() != "Forked" {
+		t.Errorf("upstream.Event = %s, want the struct of the fork", event)
+	}
+}
+
+func TestGenerateOverlay(t *testing.T) {
+	dir := t.TempDir()
+	edited := filepath.Join(dir, "app.go")
+	if err := os.WriteFile(edited, []byte(`package app
+
+import "example.com/upstream"
+
+type Publisher interface {
+	Publish(event upstream.Event) error
+	Flush() error
+}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	overlay := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(overlay, []byte(fmt.Sprintf(`{"Replace": {"../testdata/forked/app/app.go": %q}}`, edited)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generateFiles(t, Config{PkgPath: "../testdata/forked/app", Overlay: overlay})["app_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"example.com/upstream"
+)
+
+type Flush func() error
+type Publish func(event upstream.Event) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReadOverlayRejectsDeletions(t *testing.T) {
+	overlay := filepath.Join(t.TempDir(), "overlay.json")
+	if err := os.WriteFile(overlay, []byte(`{"Replace": {"app.go": ""}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := readOverlay(overlay)
+	if want := "--overlay " + overlay + " deletes app.go, which is not supported"; err == nil || err.Error() != want {
+		t.Errorf("readOverlay() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateSkipsPackagesWithoutInterfaces(t *testing.T) {
+	// Of the packages of testdata/mixed, only services declares an interface.
+	files := generateFiles(t, Config{PkgPath: "../testdata/mixed/..."})
+	delete(files, manifestFileName)
+
+	want := map[string]string{
+		"services_functypes.go": generatedHeader + `
+
+package functypes
+
+type Send func(to string, body string) error
+`,
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Generate() = %v, want %v", files, want)
+	}
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/types"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lockHeader is the comment on the first line of the --lock file.
+const lockHeader = "# Interfaces generated by functypes and the hashes of their signatures. Used by --frozen and --keep-aliases."
+
+// lockEntry is the entry of an interface in the --lock file.
+type lockEntry struct {
+	// hash is the hash of the interface's signatures, see signatureHash.
+	hash string
+	// methods are the hashes of the signatures of the interface's converted methods by name, see methodHash. Only recorded with --keep-aliases, which detects renamed methods with them.
+	methods map[string]string
+	// aliases are the old names of renamed methods, mapped to their current names, which --keep-aliases keeps emitting deprecated aliases for.
+	aliases map[string]string
+}
+
+// lockKey returns the key of the interface in the --lock file: its import path and name.
+func lockKey(named *types.Named) string {
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+// signatureHash returns a hash of the interface's type parameters and method signatures, which changes whenever one of them does.
+func signatureHash(named *types.Named) string {
+	builder := &strings.Builder{}
+	for i := 0; i < named.TypeParams().Len(); i++ {
+		tparam := named.TypeParams().At(i)
+		builder.WriteString(tparam.Obj().Name() + " " + types.TypeString(tparam.Constraint(), nil) + "\n")
+	}
+	builder.WriteString(types.TypeString(named.Underlying(), nil))
+
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// methodHash returns a hash of the method's signature, which changes whenever it does, but not when the method is renamed.
+func methodHash(meth *types.Func) string {
+	sum := sha256.Sum256([]byte(types.TypeString(meth.Type(), nil)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// readLock returns the entries listed in the --lock file by interface, or nil if the file doesn't exist yet.
+// An entry is the interface and its signature hash, optionally followed by the <method>=<hash> and <old name>><name> fields of --keep-aliases.
+func readLock(lockPath string) (map[string]lockEntry, error) {
+	content, err := os.ReadFile(lockPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", lockPath, err)
+	}
+
+	entries := make(map[string]lockEntry)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s: invalid line %q, expected <interface> <hash>", lockPath, line)
+		}
+		entry := lockEntry{hash: fields[1]}
+		for _, field := range fields[2:] {
+			if method, hash, ok := strings.Cut(field, "="); ok {
+				if entry.methods == nil {
+					entry.methods = make(map[string]string)
+				}
+				entry.methods[method] = hash
+			} else if old, name, ok := strings.Cut(field, ">"); ok {
+				if entry.aliases == nil {
+					entry.aliases = make(map[string]string)
+				}
+				entry.aliases[old] = name
+			} else {
+				return nil, fmt.Errorf("%s: invalid field %q of %s, expected <method>=<hash> or <old name>><name>", lockPath, field, fields[0])
+			}
+		}
+		entries[fields[0]] = entry
+	}
+	return entries, nil
+}
+
+// lockLine returns the line of the interface's entry in the --lock file.
+func lockLine(key string, entry lockEntry) string {
+	fields := []string{key, entry.hash}
+	for _, method := range sortedKeys(entry.methods) {
+		fields = append(fields, method+"="+entry.methods[method])
+	}
+	for _, old := range sortedKeys(entry.aliases) {
+		fields = append(fields, old+">"+entry.aliases[old])
+	}
+	return strings.Join(fields, " ")
+}
+
+// checkLock implements --frozen: it fails if an interface was generated that isn't listed in the --lock file, so the scope of the generated code can't grow by accident.
+// Interfaces whose signatures changed since they were locked only get a warning, since changing an interface is expected to change its function types.
+func (g *generator) checkLock() error {
+	locked, err := readLock(g.cfg.Lock)
+	if err != nil {
+		return err
+	}
+	if locked == nil {
+		return fmt.Errorf("--frozen: the lock file %s doesn't exist, run without --frozen to create it", g.cfg.Lock)
+	}
+
+	var added []string
+	for _, key := range sortedKeys(g.locked) {
+		entry, ok := locked[key]
+		if !ok {
+			added = append(added, key)
+			continue
+		}
+		if entry.hash != g.locked[key].hash {
+			logrus.Warnf("the signatures of %s changed since it was locked in %s", key, g.cfg.Lock)
+		}
+	}
+
+	if len(added) > 0 {
+		return fmt.Errorf("--frozen: the interfaces %s are not listed in %s, run without --frozen to add them", strings.Join(added, ", "), g.cfg.Lock)
+	}
+	return nil
+}
+
+// writeLock records the generated interfaces and their signature hashes in the --lock file.
+// With --update only some interfaces are generated, so the entries of the others are kept.
+func (g *generator) writeLock() error {
+	entries := g.locked
+	if g.updateNames != nil {
+		previous, err := readLock(g.cfg.Lock)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			previous = make(map[string]lockEntry)
+		}
+		for key, entry := range g.locked {
+			previous[key] = entry
+		}
+		entries = previous
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(lockHeader + "\n")
+	for _, key := range sortedKeys(entries) {
+		builder.WriteString(lockLine(key, entries[key]) + "\n")
+	}
+
+	if err := os.WriteFile(g.cfg.Lock, []byte(builder.String()), filePerm); err != nil {
+		return fmt.Errorf("write %s with perm %d: %w", g.cfg.Lock, filePerm, err)
+	}
+	logrus.Infof("saved %s", g.cfg.Lock)
+	return nil
+}
+
+// sortedKeys returns the keys of the map in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFrozen(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "functypes.lock")
+	generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", Lock: lockPath})
+
+	content, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || lines[0] != lockHeader || !strings.HasPrefix(lines[1], "github.com/eaardal/functypes/testdata.MyInterface ") {
+		t.Fatalf("lock file =\n%s\nwant the header and MyInterface", content)
+	}
+
+	// The locked interfaces are generated as before.
+	generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", Lock: lockPath, Frozen: true})
+
+	// Reader wasn't locked.
+	err = Generate(Config{PkgPath: "../testdata", OutDir: t.TempDir(), Include: "^(MyInterface|Reader)$", Lock: lockPath, Frozen: true})
+	want := "--frozen: the interfaces github.com/eaardal/functypes/testdata.Reader are not listed in " + lockPath + ", run without --frozen to add them"
+	if err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+
+	after, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(content) {
+		t.Errorf("lock file =\n%s\nwant it unchanged by --frozen\n%s", after, content)
+	}
+}
+
+func TestGenerateFrozenWithoutLockFile(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "functypes.lock")
+
+	err := Generate(Config{PkgPath: "../testdata", OutDir: t.TempDir(), Lock: lockPath, Frozen: true})
+	want := "--frozen: the lock file " + lockPath + " doesn't exist, run without --frozen to create it"
+	if err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+}
+
+func TestFrozenRequiresLock(t *testing.T) {
+	_, err := newGenerator(Config{Frozen: true})
+	if want := "--frozen requires --lock"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateKeepAliases(t *testing.T) {
+	// renamed.lock was written while Remove was still named Delete.
+	original, err := os.ReadFile("../testdata/renamed/renamed.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(t.TempDir(), "renamed.lock")
+	if err := os.WriteFile(lockPath, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := generatedHeader + `
+
+package functypes
+
+type Get func(id string) (string, error)
+type Remove func(id string) error
+
+// Deprecated: Use Remove instead, Repository.Delete was renamed.
+type Delete = Remove
+`
+	// The alias is recorded in the lock file, so it's kept in later runs, where Delete isn't a method of the previous run anymore.
+	for run := 1; run <= 2; run++ {
+		got := generateFiles(t, Config{PkgPath: "../testdata/renamed", Lock: lockPath, KeepAliases: true})["renamed_functypes.go"]
+		if got != want {
+			t.Errorf("run %d: Generate() =\n%s\nwant\n%s", run, got, want)
+		}
+
+		content, err := os.ReadFile(lockPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(string(content), " Get=2bd9af2c522cc91b Remove=18b152a573a01aca Delete>Remove\n") {
+			t.Errorf("run %d: lock file =\n%s\nwant the methods of Repository and the alias of Delete", run, content)
+		}
+	}
+}
+
+func TestKeepAliasesRequiresLock(t *testing.T) {
+	_, err := newGenerator(Config{KeepAliases: true})
+	if want := "--keep-aliases requires --lock, which records the method signatures it detects renamed methods by"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// manifestFileName is the name of the manifest written to the output directory, listing the files functypes generated there.
+const manifestFileName = ".functypes-manifest"
+
+// manifestHeader is the comment on the first line of the manifest.
+const manifestHeader = "# Files generated by functypes, relative to this directory. Used by --clean."
+
+// readManifest returns the file names listed in the manifest in the output directory, or nil if there is no manifest.
+func readManifest(outDir string) ([]string, error) {
+	manifestPath := filepath.Join(outDir, manifestFileName)
+	content, err := os.ReadFile(manifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", manifestPath, err)
+	}
+
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, nil
+}
+
+// writeManifest records the names of the files written to the output directory in its manifest.
+// Files listed by earlier runs are kept as long as they still exist, so several runs sharing an output directory (for example one per package) end up in the same manifest. If no files are left, the manifest is removed.
+func writeManifest(outDir string, written []string) error {
+	previous, err := readManifest(outDir)
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[string]bool)
+	for _, name := range append(previous, written...) {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			continue
+		}
+		listed[name] = true
+	}
+
+	names := make([]string, 0, len(listed))
+	for name := range listed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifestPath := filepath.Join(outDir, manifestFileName)
+	if len(names) == 0 {
+		if err := os.Remove(manifestPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("remove %s: %w", manifestPath, err)
+		}
+		return nil
+	}
+
+	content := manifestHeader + "\n" + strings.Join(names, "\n") + "\n"
+	if err := os.WriteFile(manifestPath, []byte(content), filePerm); err != nil {
+		return fmt.Errorf("write %s with perm %d: %w", manifestPath, filePerm, err)
+	}
+	return nil
+}
+
+// clean implements --clean: it deletes the files listed in the manifests in the output directories (--out-dir and the --route directories), which is useful after interfaces were removed from the source.
+func (g *generator) clean() error {
+	outDirs := g.outDirs()
+	if g.cfg.Subpackages {
+		outDirs = append(outDirs, subpackageDirs(g.cfg.OutDir)...)
+	}
+
+	cleaned := false
+	for _, outDir := range outDirs {
+		names, err := readManifest(outDir)
+		if err != nil {
+			return err
+		}
+		if names == nil {
+			continue
+		}
+
+		if err := cleanDir(outDir, names); err != nil {
+			return err
+		}
+		cleaned = true
+	}
+
+	if !cleaned {
+		return fmt.Errorf("found no %s in %s, so there is nothing to clean", manifestFileName, strings.Join(outDirs, ", "))
+	}
+	return nil
+}
+
+// cleanDir deletes the named files in the output directory. A file is only deleted if it still carries the generatedHeader (see isGeneratedOutput for the files of custom emitters). Files which were replaced by hand-written code since they were generated are refused and stay listed in the manifest.
+func cleanDir(outDir string, names []string) error {
+	for _, name := range names {
+		filePath := filepath.Join(outDir, name)
+
+		content, err := os.ReadFile(filePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			logrus.Debugf("skipping %s because it no longer exists", filePath)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", filePath, err)
+		}
+
+		if !isGeneratedOutput(filePath, content) {
+			logrus.Warnf("refusing to delete %s because it was not generated by functypes", filePath)
+			continue
+		}
+
+		if err := os.Remove(filePath); err != nil {
+			return fmt.Errorf("remove %s: %w", filePath, err)
+		}
+		logrus.Infof("removed %s", filePath)
+	}
+
+	// Rewriting the manifest drops the deleted files and removes it altogether if nothing was refused.
+	return writeManifest(outDir, nil)
+}
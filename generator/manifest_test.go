@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGenerateClean(t *testing.T) {
+	outDir := t.TempDir()
+	generateFiles(t, Config{PkgPath: "../testdata", OutDir: outDir})
+	generateFiles(t, Config{PkgPath: "../testdata/widen", OutDir: outDir})
+
+	// widen's file has been replaced by hand-written code since it was generated, and handwritten.go was never generated.
+	for name, content := range map[string]string{
+		"widen_functypes.go": "package functypes\n",
+		"handwritten.go":     "package functypes\n",
+	} {
+		if err := os.WriteFile(filepath.Join(outDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest, err := readManifest(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"testdata_functypes.go", "widen_functypes.go"}; !reflect.DeepEqual(manifest, want) {
+		t.Fatalf("readManifest() = %v, want %v", manifest, want)
+	}
+
+	if err := Generate(Config{OutDir: outDir, Clean: true}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	sort.Strings(got)
+	// Only the generated file is deleted, and the refused file stays listed in the manifest.
+	if want := []string{manifestFileName, "handwritten.go", "widen_functypes.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("files after Clean = %v, want %v", got, want)
+	}
+
+	manifest, err = readManifest(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"widen_functypes.go"}; !reflect.DeepEqual(manifest, want) {
+		t.Errorf("readManifest() after Clean = %v, want %v", manifest, want)
+	}
+}
+
+func TestGenerateCleanWithoutManifest(t *testing.T) {
+	outDir := t.TempDir()
+	err := Generate(Config{OutDir: outDir, Clean: true})
+	if want := "found no " + manifestFileName + " in " + outDir + ", so there is nothing to clean"; err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+}
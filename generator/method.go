@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// methodOrigin is an interface declaring the method selected by --method, and the method's signature there.
+type methodOrigin struct {
+	// iface is the lock key of the interface, see lockKey.
+	iface string
+	sig   *types.Signature
+}
+
+// sharesMethod checks the signature of the --method method in the interface with those of the interfaces seen so far, and returns true if its function type is declared already.
+// Identical signatures share the function type of the first interface declaring the method. A signature differing from one seen before is recorded as a collision, reported by checkMethodCollisions, and its function type isn't rendered.
+func (g *generator) sharesMethod(named *types.Named, sig *types.Signature) bool {
+	key := lockKey(named)
+	for _, origin := range g.methodOrigins {
+		// Every output directory is rendered twice (see collectImports), so the interface can be seen already.
+		if origin.iface == key {
+			return false
+		}
+	}
+
+	for _, origin := range g.methodOrigins {
+		if types.Identical(origin.sig, sig) {
+			logrus.Debugf("skipping method %s of %s because it has the same signature as in %s, whose function type is used", g.cfg.Method, key, origin.iface)
+			return true
+		}
+	}
+
+	if len(g.methodOrigins) > 0 {
+		first := g.methodOrigins[0]
+		g.methodCollisions[fmt.Sprintf("%s: %s", key, sig)] = true
+		g.methodCollisions[fmt.Sprintf("%s: %s", first.iface, first.sig)] = true
+		return true
+	}
+
+	g.methodOrigins = append(g.methodOrigins, methodOrigin{iface: key, sig: sig})
+	return false
+}
+
+// checkMethodCollisions returns an error listing the interfaces declaring the --method method with differing signatures, whose function types would collide.
+func (g *generator) checkMethodCollisions() error {
+	if len(g.methodCollisions) == 0 {
+		return nil
+	}
+
+	collisions := make([]string, 0, len(g.methodCollisions))
+	for collision := range g.methodCollisions {
+		collisions = append(collisions, collision)
+	}
+	sort.Strings(collisions)
+	return fmt.Errorf("--method %s: the method has different signatures in these interfaces, whose function types would collide (narrow them down with --include or --exclude):\n\t%s", g.cfg.Method, strings.Join(collisions, "\n\t"))
+}
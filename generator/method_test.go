@@ -0,0 +1,29 @@
+package generator
+
+import "testing"
+
+func TestGenerateMethod(t *testing.T) {
+	// File, Conn and Body share the signature of Read, which differs in Queue only.
+	got := generateFiles(t, Config{PkgPath: "../testdata/readers", Method: "Read", Exclude: "^Queue$"})["readers_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type Read func(p []byte) (n int, err error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateMethodCollisions(t *testing.T) {
+	err := Generate(Config{PkgPath: "../testdata/readers", OutDir: t.TempDir(), Method: "Read"})
+
+	want := "--method Read: the method has different signatures in these interfaces, whose function types would collide (narrow them down with --include or --exclude):\n" +
+		"\tgithub.com/eaardal/functypes/testdata/readers.Body: func(p []byte) (n int, err error)\n" +
+		"\tgithub.com/eaardal/functypes/testdata/readers.Queue: func() (github.com/eaardal/functypes/testdata/readers.Message, error)"
+	if err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+}
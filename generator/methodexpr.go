@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// methodExpressionSignature returns the signature of the method expression of a method of the named interface, like func(r Reader, p []byte) (n int, err error) for Reader.Read: the signature with the interface as its first parameter (see --method-expressions).
+// The receiver is only named if the parameters are, since Go requires either all or none of them to be. A generic interface is its own instantiation, like Set[T], so the function type gets all of the interface's type parameters.
+func methodExpressionSignature(named *types.Named, sig *types.Signature) *types.Signature {
+	recvType := types.Type(named)
+	if tparams := named.TypeParams(); tparams.Len() > 0 {
+		args := make([]types.Type, tparams.Len())
+		for i := 0; i < tparams.Len(); i++ {
+			args[i] = tparams.At(i)
+		}
+		if instance, err := types.Instantiate(nil, named, args, false); err == nil {
+			recvType = instance
+		}
+	}
+
+	params := sig.Params()
+	taken := make(map[string]bool)
+	paramsNamed := false
+	for i := 0; i < params.Len(); i++ {
+		name := params.At(i).Name()
+		taken[name] = true
+		paramsNamed = paramsNamed || (name != "" && name != "_")
+	}
+
+	var recvName string
+	if paramsNamed {
+		recvName = uniqueName(receiverName(named.Obj().Name()), taken)
+	}
+
+	vars := []*types.Var{types.NewParam(named.Obj().Pos(), named.Obj().Pkg(), recvName, recvType)}
+	for i := 0; i < params.Len(); i++ {
+		vars = append(vars, params.At(i))
+	}
+	return types.NewSignatureType(nil, nil, nil, types.NewTuple(vars...), sig.Results(), sig.Variadic())
+}
+
+// receiverName returns the conventional receiver name for a type: its first letter in lower case, like r for Reader.
+func receiverName(typeName string) string {
+	for _, c := range typeName {
+		return string(unicode.ToLower(c))
+	}
+	return "recv"
+}
+
+// methodExpressionConflicts returns the flags set in the config which can't be used with --method-expressions, comma separated. They generate code with the signature of the methods, like adapters calling the function types without a receiver.
+func methodExpressionConflicts(cfg Config) string {
+	var conflicts []string
+	for flag, set := range map[string]bool{
+		"--emit-adapter":   cfg.EmitAdapter,
+		"--emit-vtable":    cfg.EmitVTable,
+		"--emit-must":      cfg.EmitMust,
+		"--emit-ctx-guard": cfg.EmitCtxGuard,
+		"--emit-stubs":     cfg.EmitStubs,
+		"--emit-zero-args": cfg.EmitZeroArgs,
+	} {
+		if set {
+			conflicts = append(conflicts, flag)
+		}
+	}
+	sort.Strings(conflicts)
+	return strings.Join(conflicts, ", ")
+}
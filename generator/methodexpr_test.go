@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMethodExpressions(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^Reader$", MethodExpressions: true})["testdata_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata"
+)
+
+type Read func(r testdata.Reader, p []byte) (n int, err error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^Reader$", MethodExpressions: true}, map[string]string{
+		"assign_test.go": `package functypes
+
+import "github.com/eaardal/functypes/testdata"
+
+var _ Read = testdata.Reader.Read
+`,
+	})
+}
+
+func TestGenerateMethodExpressionsSkipsUnexportedInterfaces(t *testing.T) {
+	logged := warnings(t)
+	files := generateFiles(t, Config{PkgPath: "../testdata/localctx", MethodExpressions: true})
+
+	if got := files["localctx_functypes.go"]; strings.Contains(got, "localctx.context") {
+		t.Errorf("Generate() =\n%s\nwant it not to reference localctx.context", got)
+	}
+	want := "skipping interface context because it's unexported, so its method expressions can only be referenced inside github.com/eaardal/functypes/testdata/localctx (see --method-expressions)"
+	if got := strings.Join(logged(), "\n"); !strings.Contains(got, want) {
+		t.Errorf("warnings =\n%s\nwant them to contain\n%s", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// stringifyMustWrapper will take an interface method whose last result is an error and emit a Must<Method> function, which wraps the function type so that it panics instead of returning a non-nil error.
+// The error result is whatever --error-type points to, or the builtin error by default. Returns an empty string if the method doesn't return an error.
+func (r *renderer) stringifyMustWrapper(meth *types.Func, typeParams typeParamLists) string {
+	sig, ok := meth.Type().Underlying().(*types.Signature)
+	if !ok || !r.returnsError(sig) {
+		return ""
+	}
+
+	reserved := map[string]bool{"f": true, "err": true}
+	params := r.forwardParams(sig, reserved)
+
+	var resultTypes, resultNames []string
+	for i := 0; i < sig.Results().Len()-1; i++ {
+		resultTypes = append(resultTypes, r.typeString(sig.Results().At(i).Type()))
+
+		name := uniqueName(fmt.Sprintf("r%d", i), reserved)
+		reserved[name] = true
+		resultNames = append(resultNames, name)
+	}
+	signature := fmt.Sprintf("func(%s)%s", params.decl, resultList(resultTypes))
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// Must%s wraps f so that it panics instead of returning a non-nil error.\n", meth.Name()))
+	builder.WriteString(fmt.Sprintf("func Must%s%s(f %s%s) %s {\n", meth.Name(), typeParams.decl, meth.Name(), typeParams.use, signature))
+	builder.WriteString(fmt.Sprintf("\treturn %s {\n", signature))
+	builder.WriteString(fmt.Sprintf("\t\t%s := f(%s)\n", strings.Join(append(resultNames, "err"), ", "), params.args))
+	builder.WriteString("\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n")
+	if len(resultNames) > 0 {
+		builder.WriteString(fmt.Sprintf("\t\treturn %s\n", strings.Join(resultNames, ", ")))
+	}
+	builder.WriteString("\t}\n}")
+	return builder.String()
+}
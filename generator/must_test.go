@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMust(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", EmitMust: true})["testdata_functypes.go"]
+
+	for _, want := range []string{
+		"// MustBar wraps f so that it panics instead of returning a non-nil error.\nfunc MustBar(f Bar) func(a string) {\n\treturn func(a string) {\n\t\terr := f(a)\n\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n\t}\n}\n",
+		"// MustAbc wraps f so that it panics instead of returning a non-nil error.\nfunc MustAbc(f Abc) func() string {\n\treturn func() string {\n\t\tr0, err := f()\n\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n\t\treturn r0\n\t}\n}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+	// Methods without an error result don't get a wrapper.
+	if strings.Contains(got, "MustFoo") {
+		t.Errorf("Generate() =\n%s\nwant no MustFoo wrapper", got)
+	}
+}
+
+func TestGenerateMustWithErrorType(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/domainerr", EmitMust: true, ErrorType: "domainerr.Error"})["domainerr_functypes.go"]
+
+	// The *Error result drives the wrapper, like an error result would.
+	want := "func MustLoad(f Load) func(key string) string {\n\treturn func(key string) string {\n\t\tr0, err := f(key)\n\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n\t\treturn r0\n\t}\n}\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+	if !strings.Contains(got, "func MustSave(f Save) func(key string, value string) {") {
+		t.Errorf("Generate() =\n%s\nwant a MustSave wrapper", got)
+	}
+}
@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// funcTypeName returns the name of the function type generated for the method of the named interface: the method's name, or <Interface><Method> if methods of that name are converted for several interfaces of the output directory, like ReadCloserClose and WriteCloserClose. The files of an output directory make up one package, where the function types would collide otherwise.
+// The other methods of those interfaces get the prefix as well, like ReadCloserRead, so the function types of an interface are named alike. With --flatten-unique they keep the method name instead.
+// A method named like a declaration of the --qualify-relative-to package gets the prefix too, since its function type would be declared in the same package. An interface Read with a method Read gets the function type ReadRead then, and a method Config the function type <Interface>Config next to a struct Config.
+// Interfaces of the same name in different packages rendered to the output directory, like the Store of a/one and b/two, would still collide with their methods of the same name, so those get the package name as another prefix: OneStoreGet and TwoStoreGet.
+// The method name is capitalized, so the unexported methods converted with --export-unexported get exported function types.
+func (r *renderer) funcTypeName(iface *types.TypeName, methodName string) string {
+	if r.nameTemplate != nil {
+		name := r.templateName(iface, methodName)
+		if r.namesLocalDeclaration(name) {
+			return iface.Name() + name
+		}
+		return name
+	}
+
+	name := capitalize(methodName)
+	c := r.collisions[r.outDir]
+	key := iface.Pkg().Path() + "." + iface.Name()
+	if !c.prefixed(key, methodName, r.cfg.FlattenUnique) && !r.namesLocalDeclaration(name) {
+		return name
+	}
+	if c.pkgPrefixed(key, methodName, r.cfg.FlattenUnique) {
+		return capitalize(iface.Pkg().Name()) + iface.Name() + name
+	}
+	return iface.Name() + name
+}
+
+// nameTemplateData is what --name-template is executed with for each function type.
+type nameTemplateData struct {
+	// Interface and Method are the names of the interface and of the method the function type is generated for, and Package the name of the interface's package.
+	Interface, Method, Package string
+}
+
+// parseNameTemplate parses --name-template, and checks that it renders a Go identifier for a sample method, like Read of io.Reader, so mistakes like an unknown field fail before anything is loaded.
+func parseNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("name-template").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("--name-template: %v", err)
+	}
+
+	builder := &strings.Builder{}
+	if err := tmpl.Execute(builder, nameTemplateData{Interface: "Reader", Method: "Read", Package: "io"}); err != nil {
+		return nil, fmt.Errorf("--name-template: %v", err)
+	}
+	if name := capitalize(builder.String()); !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("--name-template must render a Go identifier, got %q for the method Read of io.Reader", name)
+	}
+	return tmpl, nil
+}
+
+// templateName returns the name --name-template renders for the method of the named interface, capitalized like the default names. It has no prefixes added to keep it unique, so names the template renders twice are reported by checkIdentifiers.
+func (r *renderer) templateName(iface *types.TypeName, methodName string) string {
+	builder := &strings.Builder{}
+	data := nameTemplateData{Interface: iface.Name(), Method: methodName, Package: iface.Pkg().Name()}
+	if err := r.nameTemplate.Execute(builder, data); err != nil {
+		// The template rendered a name for the sample method, so it can only fail for some methods, like when it indexes into a name.
+		r.log.Warnf("using the method name for %s of %s because --name-template failed: %v", methodName, iface.Name(), err)
+		return capitalize(methodName)
+	}
+	return capitalize(builder.String())
+}
+
+// capitalize returns the name with its first letter in upper case.
+func capitalize(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// namesLocalDeclaration returns true if the --qualify-relative-to package declares something with the given name, other than in the files generated into the output directory.
+// Those files hold the function types of a previous run, which are named like their methods on purpose and are replaced by this one.
+func (r *renderer) namesLocalDeclaration(name string) bool {
+	if r.localPkg == nil || r.localPkg.Scope().Lookup(name) == nil {
+		return false
+	}
+	return !r.generatedNames(r.outDir)[name]
+}
+
+// generatedNames returns the names declared at the top level of the .go files in the output directory which carry the generatedHeader. They're read once per output directory.
+func (g *generator) generatedNames(outDir string) map[string]bool {
+	if names, ok := g.generatedDecls[outDir]; ok {
+		return names
+	}
+
+	names := make(map[string]bool)
+	matches, _ := filepath.Glob(filepath.Join(outDir, "*.go"))
+	for _, match := range matches {
+		content, err := os.ReadFile(match)
+		if err != nil || !isGeneratedFile(content) {
+			continue
+		}
+		decls, err := topLevelDecls(match, content, true)
+		if err != nil {
+			continue
+		}
+		for decl := range decls {
+			// Specs declaring several values are keyed by all their names, like a, b.
+			for _, name := range strings.Split(decl, ", ") {
+				names[name] = true
+			}
+		}
+	}
+
+	if g.generatedDecls == nil {
+		g.generatedDecls = make(map[string]map[string]bool)
+	}
+	g.generatedDecls[outDir] = names
+	return names
+}
+
+// collisions are the methods whose function types collide in an output directory, see collidingMethods.
+type collisions struct {
+	// methods are the names of the methods converted for several interfaces, and ifaces the lock keys of the interfaces they're converted for.
+	methods map[string]bool
+	ifaces  map[string]bool
+	// pkgMethods are the methods of interfaces named like an interface of another package they collide with, by lock key and method name, and pkgIfaces the lock keys of those interfaces.
+	pkgMethods map[string]bool
+	pkgIfaces  map[string]bool
+}
+
+// prefixed returns true if the function type of the method of the interface with the lock key gets the interface name as prefix: if it collides, or without --flatten-unique if another method of the interface does.
+func (c collisions) prefixed(key, methodName string, flattenUnique bool) bool {
+	return c.methods[methodName] || (!flattenUnique && c.ifaces[key])
+}
+
+// pkgPrefixed returns true if the function type of the method of the interface with the lock key gets the package name as another prefix, like prefixed.
+func (c collisions) pkgPrefixed(key, methodName string, flattenUnique bool) bool {
+	return c.pkgMethods[key+"."+methodName] || (!flattenUnique && c.pkgIfaces[key])
+}
+
+// collidingMethods is the first pass of a run: it renders the packages into each output directory without logging, and returns the methods converted for more than one interface by output directory. Those get the interface name as prefix, see funcTypeName.
+// The files of an output directory make up one package, so with a package pattern the methods of all packages rendered to it are taken into account. The methods of interfaces sharing their name with an interface of another package, which would collide even with the prefix, are returned too.
+func (g *generator) collidingMethods(pkgs []*packages.Package) (map[string]collisions, error) {
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+
+	byOutDir := make(map[string]collisions)
+	for _, outDir := range g.outDirs() {
+		r := g.newRenderer(outDir)
+		r.log = discard
+		if _, err := r.processPackages(pkgs, &strings.Builder{}); err != nil {
+			return nil, err
+		}
+
+		c := collisions{methods: make(map[string]bool), ifaces: make(map[string]bool), pkgMethods: make(map[string]bool), pkgIfaces: make(map[string]bool)}
+		for method, ifaces := range r.convertedBy {
+			if len(ifaces) < 2 {
+				continue
+			}
+			c.methods[method] = true
+
+			// The lock keys of interfaces by their name, which only share one with interfaces of other packages.
+			byName := make(map[string][]string)
+			for key := range ifaces {
+				c.ifaces[key] = true
+				name := key[strings.LastIndex(key, ".")+1:]
+				byName[name] = append(byName[name], key)
+			}
+			for _, keys := range byName {
+				if len(keys) < 2 {
+					continue
+				}
+				for _, key := range keys {
+					c.pkgMethods[key+"."+method] = true
+					c.pkgIfaces[key] = true
+				}
+			}
+		}
+		byOutDir[outDir] = c
+	}
+	return byOutDir, nil
+}
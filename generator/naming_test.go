@@ -0,0 +1,338 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePrefixesCollidingMethods(t *testing.T) {
+	tests := []struct {
+		name          string
+		flattenUnique bool
+		want          string
+		// wantFields are fields of the adapters, which follow the prefixed names.
+		wantFields []string
+	}{
+		{
+			// Only the Close methods collide, but all methods of ReadCloser get its name as prefix.
+			name:       "interfaces with colliding methods",
+			want:       "type CloserClose func() error\ntype ReadCloserClose func() error\ntype ReadCloserName func() string\ntype ReadCloserRead func(p []byte) (n int, err error)\n",
+			wantFields: []string{"\tCloseFunc CloserClose\n", "\tCloseFunc ReadCloserClose\n", "\tReadFunc  ReadCloserRead\n"},
+		},
+		{
+			name:          "only colliding methods with --flatten-unique",
+			flattenUnique: true,
+			want:          "type CloserClose func() error\ntype ReadCloserClose func() error\ntype Name func() string\ntype Read func(p []byte) (n int, err error)\n",
+			wantFields:    []string{"\tCloseFunc CloserClose\n", "\tCloseFunc ReadCloserClose\n", "\tReadFunc  Read\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/embedded", FlattenUnique: tt.flattenUnique})["embedded_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+
+			withAdapters := generateFiles(t, Config{PkgPath: "../testdata/embedded", FlattenUnique: tt.flattenUnique, EmitAdapter: true, Validate: true})["embedded_functypes.go"]
+			for _, field := range tt.wantFields {
+				if !strings.Contains(withAdapters, field) {
+					t.Errorf("Generate() =\n%s\nwant the adapter field %q", withAdapters, strings.TrimSpace(field))
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMethodNamedLikeItsInterface(t *testing.T) {
+	tests := []struct {
+		name              string
+		qualifyRelativeTo string
+		nameTemplate      string
+		want              string
+	}{
+		{
+			// The function types are declared in another package than the interface, so Read can keep its name.
+			name: "functypes package",
+			want: `package functypes
+
+type Close func() error
+type Read func(p []byte) (n int, err error)
+`,
+		},
+		{
+			name:              "interface package",
+			qualifyRelativeTo: "github.com/eaardal/functypes/testdata/selfnamed",
+			want: `package selfnamed
+
+type Close func() error
+type ReadRead func(p []byte) (n int, err error)
+`,
+		},
+		{
+			name:         "name template with the interface name",
+			nameTemplate: "{{.Interface}}{{.Method}}",
+			want: `package functypes
+
+type CloserClose func() error
+type ReadRead func(p []byte) (n int, err error)
+`,
+		},
+		{
+			// The template renders the interface name for Read, which gets the interface name as prefix like a default name.
+			name:              "name template in the interface package",
+			qualifyRelativeTo: "github.com/eaardal/functypes/testdata/selfnamed",
+			nameTemplate:      "{{.Method}}",
+			want: `package selfnamed
+
+type Close func() error
+type ReadRead func(p []byte) (n int, err error)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/selfnamed", QualifyRelativeTo: tt.qualifyRelativeTo, NameTemplate: tt.nameTemplate, Validate: true})["selfnamed_functypes.go"]
+
+			want := generatedHeader + "\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateNameTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		nameTemplate string
+		want         string
+	}{
+		{
+			name:         "suffix",
+			nameTemplate: "{{.Method}}Func",
+			want:         "type CloseFunc func() error\ntype ReadFunc func(p []byte) (n int, err error)\n",
+		},
+		{
+			// The first letter is capitalized, so the names of the function types stay exported.
+			name:         "package name",
+			nameTemplate: "{{.Package}}{{.Method}}",
+			want:         "type SelfnamedClose func() error\ntype SelfnamedRead func(p []byte) (n int, err error)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/selfnamed", NameTemplate: tt.nameTemplate, Validate: true})["selfnamed_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateNameTemplateCollisions(t *testing.T) {
+	// The Close methods of Closer and ReadCloser get the same name, which isn't prefixed with the interface name.
+	err := Generate(Config{PkgPath: "../testdata/embedded", OutDir: t.TempDir(), NameTemplate: "{{.Method}}Func"})
+	for _, want := range []string{"type CloseFunc is declared already", "the function types are named by --name-template"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Generate() error = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestNameTemplateIsValidated(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "unparsable",
+			cfg:  Config{NameTemplate: "{{.Method"},
+			want: "--name-template: template: name-template:1: unclosed action",
+		},
+		{
+			name: "unknown field",
+			cfg:  Config{NameTemplate: "{{.Name}}"},
+			want: "--name-template: template: name-template:1:2: executing \"name-template\" at <.Name>: can't evaluate field Name in type generator.nameTemplateData",
+		},
+		{
+			name: "not an identifier",
+			cfg:  Config{NameTemplate: "{{.Interface}}.{{.Method}}"},
+			want: "--name-template must render a Go identifier, got \"Reader.Read\" for the method Read of io.Reader",
+		},
+		{
+			name: "with --flatten-unique",
+			cfg:  Config{NameTemplate: "{{.Method}}", FlattenUnique: true},
+			want: "--flatten-unique can't be used with --name-template, whose names are used as they are",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newGenerator(tt.cfg)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("newGenerator() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPackagePatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		// want are the rendered files by name in the output directory.
+		want map[string]string
+	}{
+		{
+			name: "methods of several interfaces get the interface name as prefix",
+			cfg:  Config{PkgPath: "../testdata/embedded"},
+			want: map[string]string{
+				"embedded_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type CloserClose func() error
+type ReadCloserClose func() error
+type ReadCloserName func() string
+type ReadCloserRead func(p []byte) (n int, err error)
+`,
+			},
+		},
+		{
+			name: "interfaces of the same name in different packages get the package name as prefix too",
+			cfg:  Config{PkgPath: "../testdata/green/..."},
+			want: map[string]string{
+				"green_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type GreenYellowColor func(rgb string) error
+`,
+				"purple_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type PurpleYellowColor func(rgb string) error
+type PurpleYellowHue func(adjust int)
+`,
+			},
+		},
+		{
+			name: "only colliding methods get the prefixes with --flatten-unique",
+			cfg:  Config{PkgPath: "../testdata/green/...", FlattenUnique: true},
+			want: map[string]string{
+				"green_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type GreenYellowColor func(rgb string) error
+`,
+				"purple_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type PurpleYellowColor func(rgb string) error
+type Hue func(adjust int)
+`,
+			},
+		},
+		{
+			name: "methods filtered out don't collide",
+			cfg:  Config{PkgPath: "../testdata/green/...", ExcludeMethods: "^Color$"},
+			want: map[string]string{
+				"purple_functypes.go": `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type Hue func(adjust int)
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := t.TempDir()
+			tt.cfg.OutDir = outDir
+			tt.cfg.Validate = true
+
+			files, err := Render(tt.cfg)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			got := make(map[string]string)
+			for outFilePath, content := range files {
+				got[strings.TrimPrefix(outFilePath, outDir+"/")] = string(content)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPatternMatchingNoPackages(t *testing.T) {
+	// A directory without .go files below it.
+	empty := "./" + writeTestPackage(t, nil) + "/..."
+
+	tests := []struct {
+		name    string
+		pkgPath string
+		wantErr string
+	}{
+		{
+			name:    "import path pattern",
+			pkgPath: "deep/...",
+			wantErr: "the package pattern deep/... matched no packages, patterns not starting with ./ or / match import paths (use --pkg-path ./deep/... to scan the directory)",
+		},
+		{
+			name:    "relative pattern",
+			pkgPath: empty,
+			wantErr: "the package pattern " + empty + " matched no packages",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(Config{PkgPath: tt.pkgPath, OutDir: t.TempDir()})
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Render() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderSamePackageDeclarations(t *testing.T) {
+	// The package declares a struct named like the Config method, and has the function types of a previous run, which are named like their methods on purpose.
+	dir := writeTestPackage(t, map[string]string{
+		"loader.go":           "package loader\n\ntype Config struct {\n\tName string\n}\n\ntype Loader interface {\n\tConfig() Config\n\tLoad(name string) error\n}\n",
+		"loader_functypes.go": generatedHeader + "\n\npackage loader\n\ntype Config2 func() Config\ntype Load func(name string) error\n",
+	})
+
+	files, err := Render(Config{PkgPath: dir, SamePackage: true, Validate: true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := `// Code generated by functypes. DO NOT EDIT.
+
+package loader
+
+type LoaderConfig func() Config
+type Load func(name string) error
+`
+	if got := string(files[filepath.Join(dir, "loader_functypes.go")]); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
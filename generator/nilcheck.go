@@ -0,0 +1,8 @@
+package generator
+
+import "fmt"
+
+// stringifyNilCheck will take the name of a function type and emit an IsSet method reporting whether a function was assigned to it. Function types aren't comparable to anything but nil, so this reads better in option validation than comparing to nil.
+func stringifyNilCheck(name string, typeParams typeParamLists) string {
+	return fmt.Sprintf("// IsSet returns true if f is not nil.\nfunc (f %s%s) IsSet() bool {\n\treturn f != nil\n}", name, typeParams.use)
+}
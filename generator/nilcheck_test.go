@@ -0,0 +1,39 @@
+package generator
+
+import "testing"
+
+func TestGenerateNilChecks(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^Reader$", EmitNilChecks: true})["testdata_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type Read func(p []byte) (n int, err error)
+
+// IsSet returns true if f is not nil.
+func (f Read) IsSet() bool {
+	return f != nil
+}
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateNilChecksReportSetFunctions(t *testing.T) {
+	// The examples of --emit-examples check that the nil function types aren't set.
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^Reader$", EmitNilChecks: true, EmitExamples: true}, map[string]string{
+		"isset_test.go": `package functypes
+
+import "testing"
+
+func TestIsSet(t *testing.T) {
+	var read Read = func(p []byte) (int, error) { return len(p), nil }
+	if !read.IsSet() {
+		t.Error("IsSet() = false for an assigned function, want true")
+	}
+}
+`,
+	})
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"generated_functypes.go":   generatedHeader + "\n\npackage functypes\n",
+		"handwritten_functypes.go": "package functypes\n",
+		"forced_functypes.go":      "package functypes\n",
+		"protected_functypes.go":   "package functypes\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		file        string
+		force       bool
+		forceGlob   string
+		protectGlob string
+		// wantErr is the start of the expected error, if the file must not be overwritten.
+		wantErr string
+	}{
+		{
+			name: "new file",
+			file: "new_functypes.go",
+		},
+		{
+			name: "generated file",
+			file: "generated_functypes.go",
+		},
+		{
+			name:    "hand-written file",
+			file:    "handwritten_functypes.go",
+			wantErr: "refusing to overwrite " + filepath.Join(dir, "handwritten_functypes.go") + " because it was not generated by functypes (use --force",
+		},
+		{
+			name:  "hand-written file with --force",
+			file:  "handwritten_functypes.go",
+			force: true,
+		},
+		{
+			name:        "path matching --force-glob next to one matching --protect-glob",
+			file:        "forced_functypes.go",
+			forceGlob:   "forced_*.go",
+			protectGlob: "protected_*.go",
+		},
+		{
+			name:        "path matching --protect-glob next to one matching --force-glob",
+			file:        "protected_functypes.go",
+			forceGlob:   "forced_*.go",
+			protectGlob: "protected_*.go",
+			wantErr:     "refusing to overwrite " + filepath.Join(dir, "protected_functypes.go") + " because it was not generated by functypes and it matches --protect-glob",
+		},
+		{
+			name:        "path matching --protect-glob with --force",
+			file:        "protected_functypes.go",
+			force:       true,
+			protectGlob: filepath.Join(dir, "protected_*.go"),
+			wantErr:     "refusing to overwrite",
+		},
+		{
+			name:        "generated file matching --protect-glob",
+			file:        "generated_functypes.go",
+			protectGlob: "*.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := newGenerator(Config{Force: tt.force, ForceGlob: tt.forceGlob, ProtectGlob: tt.protectGlob})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = g.checkOverwrite(filepath.Join(dir, tt.file))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkOverwrite() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("checkOverwrite() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
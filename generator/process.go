@@ -0,0 +1,585 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// renderer stringifies the declarations of a single generated file. Every package referenced along the way is collected by its importSet, so the file can import it.
+type renderer struct {
+	*generator
+	imports *importSet
+	// outDir is the output directory of the file. Only the interfaces routed to it are rendered, see outDirFor.
+	outDir string
+	// log is where the progress of the rendering is logged. It's discarded when rendering only to collect the imports, see collectImports.
+	log logrus.FieldLogger
+
+	// widenCandidates are the interfaces parameters can be widened to with --widen-params, see widenSignature.
+	widenCandidates []*types.Named
+
+	// registered are the function types registered by the init function of --emit-init.
+	registered []string
+
+	// renderErrors are the methods whose function type isn't valid Go, see checkRendered. They're reported together once the file is rendered.
+	renderErrors []string
+
+	// examples are the function types the test file gets an example function for with --emit-examples.
+	examples []string
+
+	// replacedTypes is set once a type is rendered as its --replace-type replacement.
+	replacedTypes bool
+	// widenedParams is set once a parameter is widened to an interface with --widen-params.
+	widenedParams bool
+
+	// inlined are the types whose definition is copied into the file with --inline-package, in the order they were first referenced. inlinedSeen are the same by object, and notInlined the types of the inlined packages which can't be inlined. inlinedTypes is set once an inlined type is rendered.
+	inlined      []*types.Named
+	inlinedSeen  map[*types.TypeName]bool
+	notInlined   map[*types.TypeName]bool
+	inlinedTypes bool
+
+	// convertedBy are the interfaces whose methods were converted, by lock key, by method name. Methods converted for several interfaces collide, see collidingMethods.
+	convertedBy map[string]map[string]bool
+
+	// decls are the function types rendered, for a custom --emitter.
+	decls []GeneratedDecl
+
+	// locked are the lock entries of the interfaces rendered, by lock key (see --lock).
+	locked map[string]lockEntry
+
+	// importCounts are the number of function types referencing each package, by import path (see --imports-report).
+	importCounts map[string]int
+
+	// usesErrNotImplemented is set once a stub returns the ErrNotImplemented sentinel, so the file declares it (see --stub-error).
+	usesErrNotImplemented bool
+}
+
+// newRenderer returns a renderer for a new generated file in the given output directory.
+// A file rendered as part of the --qualify-relative-to package shares its scope with the package's own files, so an import can't be named like anything the package declares, like a local identifier named context. Those names are reserved, which gives such an import an alias like context2.
+func (g *generator) newRenderer(outDir string) *renderer {
+	var reserved []string
+	if g.localPkg != nil {
+		reserved = g.localPkg.Scope().Names()
+	}
+	imports := newImportSet(reserved...)
+	imports.local = g.localPath()
+	return &renderer{generator: g, imports: imports, outDir: outDir, log: logrus.StandardLogger(), importCounts: make(map[string]int), locked: make(map[string]lockEntry), convertedBy: make(map[string]map[string]bool), inlinedSeen: make(map[*types.TypeName]bool), notInlined: make(map[*types.TypeName]bool)}
+}
+
+// collectImports renders the file in the output directory without logging, and returns the packages it references.
+// Import names are assigned as packages are referenced, so the actual rendering starts out with these packages to assign the names in import path order instead (see importSet.assignInOrder). That way the numbered aliases of packages with the same name don't depend on the order of the declarations.
+func (g *generator) collectImports(pkgs []*packages.Package, outDir string) (*importSet, error) {
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+
+	r := g.newRenderer(outDir)
+	r.log = discard
+	if _, err := r.processPackages(pkgs, &strings.Builder{}); err != nil {
+		return nil, err
+	}
+	if r.usesErrNotImplemented {
+		r.stringifyErrNotImplemented()
+	}
+	if len(r.registered) > 0 {
+		r.stringifyInit()
+	}
+	if len(r.inlined) > 0 {
+		if _, err := r.stringifyInlined(); err != nil {
+			return nil, err
+		}
+	}
+	return r.imports, nil
+}
+
+// processPackages iterates through each package and continues to investigate each occurrance in its Scope.
+// The entries found in pkg.Types.Scope is determined based on the Mode filter in packages.Config (see packagesCfg in load.go).
+// Returns the adapters generated along the way (see --emit-adapter).
+func (r *renderer) processPackages(pkgs []*packages.Package, outputBuilder *strings.Builder) ([]adapter, error) {
+	// The interfaces the --from-type struct depends on can be declared in any package, not only the loaded ones.
+	if r.cfg.FromType != "" {
+		return r.processObjects(r.fromTypeInterfaces, outputBuilder)
+	}
+
+	var adapters []adapter
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		r.log.Debugf("%s scope: %v", pkg.PkgPath, scope.Names())
+
+		if r.cfg.WidenParams {
+			r.widenCandidates = localInterfaces(pkg.Types)
+		}
+
+		// Because we've included packages.NeedTypesInfo and packages.NeedTypes in packagesCfg, scope.Names includes the types found based on those criteria (based on all criterias in the Mode field).
+		for _, obj := range sortedObjects(scope) {
+			if !declaredInFile(pkg.Fset, obj, r.cfg.File) {
+				r.log.Debugf("skipping %s because it's not declared in %s", obj.Name(), r.cfg.File)
+				continue
+			}
+
+			a, err := r.processInterfacesInScope(obj, outputBuilder)
+			if err != nil {
+				return nil, err
+			}
+			adapters = append(adapters, a...)
+		}
+	}
+	return adapters, nil
+}
+
+// processObjects processes each of the type names like processPackages does for its packages' scopes, returning the adapters generated along the way.
+func (r *renderer) processObjects(objs []*types.TypeName, outputBuilder *strings.Builder) ([]adapter, error) {
+	var adapters []adapter
+	for _, obj := range objs {
+		a, err := r.processInterfacesInScope(obj, outputBuilder)
+		if err != nil {
+			return nil, err
+		}
+		adapters = append(adapters, a...)
+	}
+	return adapters, nil
+}
+
+// sortedObjects returns the objects declared in the scope, sorted by name.
+// scope.Names happens to be sorted already, but the generated output must not depend on that (or on anything else that could change between Go versions), so the order is guaranteed here.
+func sortedObjects(scope *types.Scope) []types.Object {
+	objs := make([]types.Object, 0, scope.Len())
+	for _, name := range scope.Names() {
+		objs = append(objs, scope.Lookup(name))
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].Name() < objs[j].Name()
+	})
+	return objs
+}
+
+// processInterfacesInScope will check if the object from the package's scope is an interface. If it is, it calls further down to extract the interface's methods.
+// Returns the adapters generated for the interface, if any.
+func (r *renderer) processInterfacesInScope(obj types.Object, builder *strings.Builder) ([]adapter, error) {
+	scopeName := obj.Name()
+
+	// Only type declarations declare interfaces, a variable of an interface type like var r io.Reader doesn't.
+	if _, ok := obj.(*types.TypeName); !ok {
+		return nil, nil
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, nil
+	}
+
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok {
+		return nil, nil
+	}
+
+	if reason := r.skipReason(scopeName); reason != "" {
+		r.log.Debugf("skipping interface %s because %s", scopeName, reason)
+		return nil, nil
+	}
+
+	// Every output directory is rendered twice (see collectImports), so interfaces are counted by key rather than each time they're processed.
+	r.processed[lockKey(named)] = true
+	if r.cfg.MaxInterfaces > 0 && len(r.processed) > r.cfg.MaxInterfaces {
+		return nil, fmt.Errorf("processing more than --max-interfaces %d interfaces (%s is one too many), narrow down --pkg-path or the filters, or raise the limit", r.cfg.MaxInterfaces, lockKey(named))
+	}
+
+	if isAggregate(iface) && r.cfg.AggregateMode == "skip" {
+		r.log.Debugf("skipping interface %s because it only embeds other interfaces (see --aggregate-mode)", scopeName)
+		return nil, nil
+	}
+
+	// An interface embedding comparable or a type set can only be used as a constraint, so it can't be the receiver of a method expression either.
+	if r.cfg.MethodExpressions && !iface.IsMethodSet() {
+		r.log.Warnf("skipping interface %s because it can only be used as a type constraint, which has no method expressions (see --method-expressions)", scopeName)
+		return nil, nil
+	}
+
+	// The receiver of the method expressions can only be referenced inside the package of an unexported interface.
+	if r.cfg.MethodExpressions && !named.Obj().Exported() && r.localPath() != named.Obj().Pkg().Path() {
+		r.log.Warnf("skipping interface %s because it's unexported, so its method expressions can only be referenced inside %s (see --method-expressions)", scopeName, named.Obj().Pkg().Path())
+		return nil, nil
+	}
+
+	// A generic interface is implemented by instantiations of it only, so whether anything implements it can't be told.
+	if r.cfg.OnlyImplemented && named.TypeParams().Len() == 0 {
+		t := r.implementer(iface)
+		if t == nil {
+			r.log.Debugf("skipping interface %s because none of the scanned types implement it (see --only-implemented)", scopeName)
+			return nil, nil
+		}
+		r.log.Debugf("interface %s is implemented by %s", scopeName, t.Obj().Name())
+	}
+
+	r.replacedTypes, r.inlinedTypes, r.widenedParams = false, false, false
+	converted, err := r.appendInterfaceMethodsToBuilder(named, iface, builder)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := lockEntry{hash: signatureHash(named)}
+	if r.cfg.KeepAliases {
+		var aliases string
+		entry.methods, entry.aliases, aliases = r.renamedMethodAliases(named, converted)
+		builder.WriteString(aliases)
+	}
+	r.locked[lockKey(named)] = entry
+
+	if r.cfg.EmitErrorVars {
+		if errorVars := r.stringifyErrorVars(named, converted); errorVars != "" {
+			builder.WriteString(errorVars + "\n")
+			r.log.WithField("interface", lockKey(named)).Infof("added: error vars of %s", scopeName)
+		}
+	}
+
+	if !r.cfg.EmitAdapter && !r.cfg.EmitVTable {
+		return nil, nil
+	}
+	reason := r.delegationSkipReason(named, iface, converted)
+
+	if r.cfg.EmitVTable {
+		// Adapters still accept the widened parameters, but a method value taking the struct can't be assigned to a function type taking the interface.
+		vtableReason := reason
+		if vtableReason == "" && r.widenedParams {
+			vtableReason = "its function types have parameters widened with --widen-params"
+		}
+		if vtableReason != "" {
+			r.log.Warnf("skipping vtable for %s because %s", scopeName, vtableReason)
+		} else {
+			builder.WriteString(r.stringifyVTable(named) + "\n")
+			r.log.WithField("interface", lockKey(named)).Infof("added: %s", vtableStructName(scopeName))
+		}
+	}
+
+	if !r.cfg.EmitAdapter {
+		return nil, nil
+	}
+	// The adapter's methods and the fields holding their functions share the names of the struct.
+	if reason == "" {
+		reason = adapterFieldCollision(iface)
+	}
+	if reason != "" {
+		r.log.Warnf("skipping adapter for %s because %s", scopeName, reason)
+		return nil, nil
+	}
+
+	var adapters []adapter
+	for _, value := range r.adapterReceivers() {
+		structName := r.adapterStructName(scopeName, value)
+		builder.WriteString(r.stringifyAdapter(structName, named.Obj(), iface, value) + "\n")
+		r.log.WithField("interface", lockKey(named)).Infof("added: %s", structName)
+
+		adapters = append(adapters, adapter{structName: structName, iface: named.Obj(), value: value})
+	}
+	return adapters, nil
+}
+
+// delegationSkipReason returns why an adapter or vtable can't be generated for the interface, which delegate between the interface's methods and their function types, or an empty string if they can.
+func (r *renderer) delegationSkipReason(named *types.Named, iface *types.Interface, converted []*types.Func) string {
+	// Every method needs a function type to delegate to or from.
+	if len(converted) != iface.NumMethods() {
+		return "not all of its methods were converted to function types"
+	}
+
+	if named.TypeParams().Len() > 0 {
+		return "generic interfaces are not supported"
+	}
+
+	// An unexported interface can only be referenced inside its package, like by the vtable's Populate and the tests asserting the adapters implement it.
+	if !named.Obj().Exported() && r.localPath() != named.Obj().Pkg().Path() {
+		return "it's unexported, so it can only be referenced inside " + named.Obj().Pkg().Path()
+	}
+
+	// Unexported methods converted with --export-unexported can only be implemented and called inside the interface's package.
+	if r.localPath() != named.Obj().Pkg().Path() {
+		for _, meth := range converted {
+			if !meth.Exported() {
+				return "it has unexported methods, which can only be implemented inside " + named.Obj().Pkg().Path()
+			}
+		}
+	}
+
+	// The interface's methods don't have the signatures of function types using replacement types.
+	if r.replacedTypes {
+		return "its function types use types replaced with --replace-type"
+	}
+	// Neither do the ones using inlined types, which are copies of the types the interface refers to.
+	if r.inlinedTypes {
+		return "its function types use types inlined with --inline-package"
+	}
+
+	// Interfaces which embed comparable or a type set can only be used as constraints, so there's no value of the interface to delegate to or from.
+	if !iface.IsMethodSet() {
+		return "it can only be used as a type constraint"
+	}
+	return ""
+}
+
+// skipReason returns why the interface with the given name isn't rendered to the renderer's file, or an empty string if it is.
+func (r *renderer) skipReason(name string) string {
+	if !r.interfaceFilter.matches(name) {
+		return "it doesn't match the interface filters"
+	}
+	if r.updateNames != nil && !r.updateNames[name] {
+		return "it's not listed by --update"
+	}
+	if dir := r.outDirFor(name); dir != r.outDir {
+		return fmt.Sprintf("it's routed to %s instead of %s", dir, r.outDir)
+	}
+	return ""
+}
+
+// appendInterfaceMethodsToBuilder will iterate through each method on the interface and stringify its signature into a standalone function type, then append that signature to the string builder.
+// The function types of a generic interface's methods are generic as well, with the interface's type parameters they need (see methodTypeParams).
+// Returns the methods that were converted to function types.
+func (r *renderer) appendInterfaceMethodsToBuilder(named *types.Named, iface *types.Interface, builder *strings.Builder) ([]*types.Func, error) {
+	ifaceName, tparams := named.Obj().Name(), named.TypeParams()
+
+	var referenced map[string]bool
+	if isAggregate(iface) && r.cfg.AggregateMode == "reference" {
+		referenced = r.renderedPartMethods(named, iface)
+	}
+
+	var converted []*types.Func
+	for i := 0; i < iface.NumMethods(); i++ {
+		meth := iface.Method(i)
+		if !r.methodFilter.matches(meth.Name()) {
+			r.log.Debugf("skipping method %s because it doesn't match the method filters", meth.Name())
+			continue
+		}
+
+		if r.cfg.MethodFilter != nil && !r.cfg.MethodFilter(ifaceName, meth.Name(), meth.Type().(*types.Signature)) {
+			r.log.Debugf("skipping method %s of %s because the method filter of the config rejected it", meth.Name(), ifaceName)
+			continue
+		}
+
+		if !r.matchesAccessorFilters(meth.Type().(*types.Signature)) {
+			r.log.Debugf("skipping method %s of %s because it doesn't match the --getters/--setters filters", meth.Name(), ifaceName)
+			continue
+		}
+
+		if r.cfg.Method != "" && meth.Name() != r.cfg.Method {
+			r.log.Debugf("skipping method %s of %s because it isn't the --method %s", meth.Name(), ifaceName, r.cfg.Method)
+			continue
+		}
+
+		if referenced[meth.Name()] {
+			r.log.Debugf("skipping method %s of %s because its function type is rendered for the interface embedding it (see --aggregate-mode)", meth.Name(), ifaceName)
+			continue
+		}
+
+		// Unexported methods can only be implemented inside the source package, so their function types would be of little use and could leak unexported types.
+		if !meth.Exported() && r.cfg.ExportUnexported {
+			r.log.Warnf("exporting the unexported method %s of %s as %s, the types of its signature may not be importable outside %s", meth.Name(), ifaceName, r.funcTypeName(named.Obj(), meth.Name()), named.Obj().Pkg().Path())
+		} else if !meth.Exported() {
+			if r.cfg.FailOnUnexportedMethods {
+				return nil, fmt.Errorf("interface %s has the unexported method %s (remove --fail-on-unexported-methods to skip it)", ifaceName, meth.Name())
+			}
+			if r.cfg.SkipUnexportedMethods {
+				r.log.Debugf("skipping unexported method %s of %s", meth.Name(), ifaceName)
+			} else {
+				r.log.Warnf("skipping unexported method %s of %s", meth.Name(), ifaceName)
+			}
+			continue
+		}
+
+		if hasInvalidType(meth.Type()) {
+			if r.cfg.ASTFallback {
+				r.appendFromSource(named, meth, tparams, builder)
+			} else {
+				r.log.Warnf("skipping method %s because its signature references types that could not be resolved", meth.Name())
+			}
+			continue
+		}
+
+		if hasUnexportedMember(meth.Type()) {
+			r.log.Warnf("skipping method %s because its signature has an anonymous struct or interface with unexported members, which can't be declared outside its package", meth.Name())
+			continue
+		}
+
+		if name := r.unexportedTypeName(meth.Type()); name != "" {
+			r.log.Warnf("skipping method %s of %s because its signature references the unexported type %s, which can't be referenced outside its package (see --qualify-relative-to)", meth.Name(), ifaceName, name)
+			continue
+		}
+
+		if name := r.shadowedBuiltin(meth.Type()); name != "" {
+			r.log.Warnf("skipping method %s of %s because its signature uses the predeclared %s, which %s shadows with its own %s", meth.Name(), ifaceName, name, r.localPkg.Path(), name)
+			continue
+		}
+
+		sig := meth.Type().(*types.Signature)
+		if r.cfg.MethodExpressions {
+			sig = methodExpressionSignature(named, sig)
+		}
+		if r.cfg.Method != "" && r.sharesMethod(named, sig) {
+			continue
+		}
+		typeParams := r.methodTypeParams(sig, tparams)
+
+		// The function type is usually named like the method, but not always (see funcTypeName). The code generated around it is named after the function type, so it's rendered with a stand-in for the method under that name.
+		name := r.funcTypeName(named.Obj(), meth.Name())
+		fn := meth
+		if name != meth.Name() {
+			fn = types.NewFunc(meth.Pos(), meth.Pkg(), name, meth.Type().(*types.Signature))
+		}
+		// The fields tell the declarations added for the method apart in structured logs, see --log-format.
+		added := r.log.WithFields(logrus.Fields{"interface": lockKey(named), "method": meth.Name(), "functype": name})
+
+		if r.cfg.Provenance {
+			builder.WriteString(r.stringifyProvenance(name, named.Obj(), meth) + "\n")
+		}
+
+		method, referenced := r.referencedPackages(func() string {
+			return r.stringifyInterfaceMethod(name, sig, typeParams)
+		})
+		if r.cfg.Explain == ifaceName {
+			r.explainMethod(ifaceName, meth, method, referenced)
+		}
+		if err := checkRendered(method); err != nil {
+			r.renderErrors = append(r.renderErrors, fmt.Sprintf("%s.%s.%s: %v\n\t\t%s", named.Obj().Pkg().Path(), ifaceName, meth.Name(), err, method))
+			continue
+		}
+		r.countImports(referenced)
+		r.decls = append(r.decls, GeneratedDecl{PkgPath: named.Obj().Pkg().Path(), Interface: ifaceName, Method: meth.Name(), Name: name, Signature: sig, Go: method})
+		builder.WriteString(method + "\n")
+		added.Infof("added: %s", method)
+
+		if r.cfg.EmitNilChecks {
+			builder.WriteString(stringifyNilCheck(name, typeParams) + "\n")
+			added.Infof("added: %s.IsSet", name)
+		}
+
+		if r.cfg.EmitMust {
+			if mustWrapper := r.stringifyMustWrapper(fn, typeParams); mustWrapper != "" {
+				builder.WriteString(mustWrapper + "\n")
+				added.Infof("added: Must%s", name)
+			}
+		}
+
+		if r.cfg.EmitCtxGuard {
+			if guard := r.stringifyCtxGuard(fn, typeParams); guard != "" {
+				builder.WriteString(guard + "\n")
+				added.Infof("added: Guard%s", name)
+			}
+		}
+
+		if r.cfg.EmitInit {
+			// A generic function type can't be registered without instantiating it.
+			if typeParams.decl == "" {
+				r.registered = append(r.registered, name)
+			} else {
+				r.log.Warnf("not registering %s because generic function types can't be registered", name)
+			}
+		}
+
+		if r.cfg.EmitExamples {
+			// Like registering, declaring a variable of a generic function type requires instantiating it.
+			if typeParams.decl == "" {
+				r.examples = append(r.examples, name)
+			} else {
+				r.log.Warnf("not emitting an example for %s because generic function types can't be declared without instantiating them", name)
+			}
+		}
+
+		if r.cfg.EmitChain {
+			builder.WriteString(r.stringifyChain(fn, typeParams) + "\n")
+			added.Infof("added: %sMiddleware", name)
+		}
+
+		if r.cfg.EmitStubs {
+			if stub := r.stringifyStub(fn, typeParams); stub != "" {
+				builder.WriteString(stub + "\n")
+				added.Infof("added: Stub%s", name)
+			}
+		}
+
+		if r.cfg.EmitZeroArgs {
+			if zeroArgs := r.stringifyZeroArgs(fn, typeParams); zeroArgs != "" {
+				builder.WriteString(zeroArgs + "\n")
+				added.Infof("added: Zero%sArgs", name)
+			}
+		}
+
+		if r.cfg.EmitResultStructs {
+			if resultStruct := r.stringifyResultStruct(fn, tparams); resultStruct != "" {
+				builder.WriteString(resultStruct + "\n")
+				added.Infof("added: %sResult", name)
+			}
+		}
+
+		converted = append(converted, meth)
+		if r.convertedBy[meth.Name()] == nil {
+			r.convertedBy[meth.Name()] = make(map[string]bool)
+		}
+		r.convertedBy[meth.Name()][lockKey(named)] = true
+	}
+	return converted, nil
+}
+
+// checkRendered returns an error if the rendered declaration isn't valid Go, which would otherwise only surface once the whole file fails to format, without telling which method caused it.
+func checkRendered(decl string) error {
+	_, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\n"+decl, parser.SkipObjectResolution)
+	// The position in the made up file is of no use, the declaration is reported along with the error.
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+		return errors.New(list[0].Msg)
+	}
+	return err
+}
+
+// stringifyInterfaceMethod will take the signature of an interface's method and convert it to a standalone function type with the same signature.
+// With --method-expressions the signature is the one of the method expression, see methodExpressionSignature. With --type-alias the function type is an alias of the signature.
+func (r *renderer) stringifyInterfaceMethod(name string, sig *types.Signature, typeParams typeParamLists) string {
+	assign := ""
+	if r.cfg.TypeAlias {
+		assign = "= "
+	}
+	return fmt.Sprintf("type %s%s %s%s", name, typeParams.decl, assign, r.typeString(r.widenSignature(sig)))
+}
+
+// stringifyResultStruct will take the results of an interface method returning more than one value and convert them to a <Method>Result struct with one field per result, for callers who'd rather pass around a single value.
+// Named results become exported fields (n becomes N), while unnamed or blank results get a synthesized name: Err for an error and Result<i> for anything else. Returns an empty string if the method doesn't return more than one value.
+// For methods of a generic interface, the struct only gets the type parameters used by the results.
+func (r *renderer) stringifyResultStruct(meth *types.Func, tparams *types.TypeParamList) string {
+	sig, ok := meth.Type().Underlying().(*types.Signature)
+	if !ok || sig.Results().Len() < 2 {
+		return ""
+	}
+	typeParams := r.methodTypeParams(types.NewSignatureType(nil, nil, nil, nil, sig.Results(), false), tparams)
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("type %sResult%s struct {\n", meth.Name(), typeParams.decl))
+
+	usedNames := make(map[string]bool)
+	for i := 0; i < sig.Results().Len(); i++ {
+		result := sig.Results().At(i)
+
+		name := r.resultFieldName(result, i)
+		if usedNames[name] {
+			name = fmt.Sprintf("Result%d", i)
+		}
+		usedNames[name] = true
+
+		builder.WriteString(fmt.Sprintf("\t%s %s\n", name, r.typeString(result.Type())))
+	}
+
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// resultFieldName returns the struct field name to use for the result at the given index in a <Method>Result struct.
+func (r *renderer) resultFieldName(result *types.Var, index int) string {
+	if name := result.Name(); name != "" && name != "_" {
+		return capitalize(name)
+	}
+	if r.isErrorType(result.Type()) {
+		return "Err"
+	}
+	return fmt.Sprintf("Result%d", index)
+}
@@ -0,0 +1,336 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerateResultStructs(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", EmitResultStructs: true})["testdata_functypes.go"]
+
+	for _, want := range []string{
+		// Named results become exported fields.
+		"type ReadResult struct {\n\tN   int\n\tErr error\n}\n",
+		// Unnamed results get synthesized names.
+		"type AbcResult struct {\n\tResult0 string\n\tErr     error\n}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+	// Methods returning a single value don't get a result struct.
+	if strings.Contains(got, "BarResult") {
+		t.Errorf("Generate() =\n%s\nwant no BarResult struct", got)
+	}
+}
+
+func TestGenerateResultStructsOfNonASCIIResultNames(t *testing.T) {
+	// The first letter of the result names takes more than a byte.
+	dir := writeTestPackage(t, map[string]string{
+		"geo.go": "package geo\n\ntype Locator interface {\n\tLocate(name string) (ñ, é float64)\n}\n",
+	})
+
+	got := generateFiles(t, Config{PkgPath: dir, EmitResultStructs: true})["geo_functypes.go"]
+
+	want := "type LocateResult struct {\n\tÑ float64\n\tÉ float64\n}\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+}
+
+func TestGenerateUnexportedMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		// wantWarnings are the expected warnings, and wantErr the expected error if the generation fails.
+		wantWarnings []string
+		wantErr      string
+	}{
+		{
+			name:         "skipped with a warning by default",
+			wantWarnings: []string{"skipping unexported method expire of Session", "skipping unexported method touch of Session"},
+		},
+		{
+			name: "skipped silently with --skip-unexported-methods",
+			cfg:  Config{SkipUnexportedMethods: true},
+		},
+		{
+			name:    "failing with --fail-on-unexported-methods",
+			cfg:     Config{FailOnUnexportedMethods: true},
+			wantErr: "interface Session has the unexported method expire (remove --fail-on-unexported-methods to skip it)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged := warnings(t)
+			cfg := tt.cfg
+			cfg.PkgPath = "../testdata/unexported"
+			cfg.Include = "^Session$"
+			cfg.OutDir = t.TempDir()
+
+			err := Generate(cfg)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			got := readFiles(t, cfg.OutDir)["unexported_functypes.go"]
+			if want := generatedHeader + "\n\npackage functypes\n\ntype ID func() string\n"; got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+			if got := logged(); !reflect.DeepEqual(got, tt.wantWarnings) {
+				t.Errorf("Generate() warned %q, want %q", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestGenerateExportUnexported(t *testing.T) {
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: "../testdata/unexported", Include: "^Session$", ExportUnexported: true})["unexported_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type ID func() string
+type Expire func(after int) (expired bool, err error)
+type Touch func()
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantWarnings := []string{
+		"exporting the unexported method expire of Session as Expire, the types of its signature may not be importable outside github.com/eaardal/functypes/testdata/unexported",
+		"exporting the unexported method touch of Session as Touch, the types of its signature may not be importable outside github.com/eaardal/functypes/testdata/unexported",
+	}
+	if got := logged(); !reflect.DeepEqual(got, wantWarnings) {
+		t.Errorf("Generate() warned %q, want %q", got, wantWarnings)
+	}
+}
+
+func TestGenerateSkipsMethodsReferencingUnexportedTypes(t *testing.T) {
+	tests := []struct {
+		name              string
+		qualifyRelativeTo string
+		want              string
+		wantWarnings      []string
+	}{
+		{
+			name: "outside the package",
+			want: "package functypes\n\ntype Name func() string\n",
+			wantWarnings: []string{
+				"skipping method Do of Runner because its signature references the unexported type unexported.opts, which can't be referenced outside its package (see --qualify-relative-to)",
+			},
+		},
+		{
+			name:              "inside the package",
+			qualifyRelativeTo: "github.com/eaardal/functypes/testdata/unexported",
+			want:              "package unexported\n\ntype Do func(o opts) error\ntype Name func() string\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged := warnings(t)
+			got := generateFiles(t, Config{PkgPath: "../testdata/unexported", Include: "^Runner$", QualifyRelativeTo: tt.qualifyRelativeTo})["unexported_functypes.go"]
+
+			if want := generatedHeader + "\n\n" + tt.want; got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+			if got := logged(); !reflect.DeepEqual(got, tt.wantWarnings) {
+				t.Errorf("Generate() warned %q, want %q", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestExportUnexportedIsValidated(t *testing.T) {
+	for _, cfg := range []Config{
+		{ExportUnexported: true, FailOnUnexportedMethods: true},
+		{ExportUnexported: true, SkipUnexportedMethods: true},
+	} {
+		_, err := newGenerator(cfg)
+		if want := "--export-unexported can't be used with --fail-on-unexported-methods or --skip-unexported-methods"; err == nil || err.Error() != want {
+			t.Errorf("newGenerator(%+v) error = %v, want %q", cfg, err, want)
+		}
+	}
+}
+
+func TestGenerateMaxInterfaces(t *testing.T) {
+	// implemented declares Archiver, Notifier and Store.
+	generateFiles(t, Config{PkgPath: "../testdata/implemented", MaxInterfaces: 3})
+
+	err := Generate(Config{PkgPath: "../testdata/implemented", OutDir: t.TempDir(), MaxInterfaces: 2})
+	want := "processing more than --max-interfaces 2 interfaces (github.com/eaardal/functypes/testdata/implemented.Store is one too many), narrow down --pkg-path or the filters, or raise the limit"
+	if err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+}
+
+func TestMaxInterfacesIsValidated(t *testing.T) {
+	_, err := newGenerator(Config{MaxInterfaces: -1})
+	if want := "--max-interfaces must not be negative, got -1"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateReportsUnrenderableMethods(t *testing.T) {
+	// A package built by hand, since no source declares a method whose name isn't an identifier.
+	pkg := types.NewPackage("example.com/broken", "broken")
+	noop := types.NewSignatureType(nil, nil, nil, nil, nil, false)
+	iface := types.NewInterfaceType([]*types.Func{
+		types.NewFunc(token.NoPos, pkg, "Fine", noop),
+		types.NewFunc(token.NoPos, pkg, "Not Valid", noop),
+	}, nil).Complete()
+	pkg.Scope().Insert(types.NewTypeName(token.NoPos, pkg, "Store", nil))
+	types.NewNamed(pkg.Scope().Lookup("Store").(*types.TypeName), iface, nil)
+	pkg.MarkComplete()
+
+	g, err := newGenerator(Config{OutDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = g.generate([]*packages.Package{{PkgPath: pkg.Path(), Name: pkg.Name(), Types: pkg, Fset: token.NewFileSet()}}, "broken")
+
+	want := "failed to render the function types of 1 methods of broken, which is a bug in functypes:\n\texample.com/broken.Store.Not Valid: expected ';', found 'func'\n\t\ttype Not Valid func()"
+	if err == nil || err.Error() != want {
+		t.Errorf("generate() error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateLogsAddedDeclarationsAsJSON(t *testing.T) {
+	logs := &bytes.Buffer{}
+	logrus.SetOutput(logs)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	t.Cleanup(func() {
+		logrus.SetOutput(io.Discard)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	})
+
+	generateFiles(t, Config{PkgPath: "../testdata", Include: "^Reader$", EmitNilChecks: true})
+
+	var got []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to parse the log line %s: %v", line, err)
+		}
+		if msg, _ := entry["msg"].(string); strings.HasPrefix(msg, "added: ") {
+			delete(entry, "time")
+			got = append(got, entry)
+		}
+	}
+
+	want := []map[string]any{
+		{"level": "info", "msg": "added: type Read func(p []byte) (n int, err error)", "interface": "github.com/eaardal/functypes/testdata.Reader", "method": "Read", "functype": "Read"},
+		{"level": "info", "msg": "added: Read.IsSet", "interface": "github.com/eaardal/functypes/testdata.Reader", "method": "Read", "functype": "Read"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged %v, want %v", got, want)
+	}
+}
+
+// writeTestPackage writes the files, by name, into a new package directory below the working directory, and returns the directory relative to it, like testpkg123.
+// Like writeSyntheticPackages, the package has to be inside the module to be loaded.
+func writeTestPackage(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp(".", "testpkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir = filepath.Clean(dir)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSortedObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		inserted []string
+		want     []string
+	}{
+		{
+			name: "empty scope",
+		},
+		{
+			name:     "sorted",
+			inserted: []string{"Alpha", "Bravo", "Charlie"},
+			want:     []string{"Alpha", "Bravo", "Charlie"},
+		},
+		{
+			name:     "unsorted",
+			inserted: []string{"Zulu", "alpha", "Mike", "Bravo"},
+			want:     []string{"Bravo", "Mike", "Zulu", "alpha"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := types.NewPackage("example.com/p", "p")
+			scope := types.NewScope(nil, token.NoPos, token.NoPos, "package p")
+			for _, name := range tt.inserted {
+				scope.Insert(types.NewTypeName(token.NoPos, pkg, name, nil))
+			}
+
+			var got []string
+			for _, obj := range sortedObjects(scope) {
+				got = append(got, obj.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortedObjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderProcessesInterfacesByName(t *testing.T) {
+	// The interfaces are declared against the order of their names, within a file and across files.
+	dir := writeTestPackage(t, map[string]string{
+		"a.go": "package sorted\n\ntype Zulu interface {\n\tZ() error\n}\n\ntype Mike interface {\n\tM() error\n}\n",
+		"b.go": "package sorted\n\ntype Alpha interface {\n\tA() error\n}\n",
+	})
+
+	outDir := t.TempDir()
+	files, err := Render(Config{PkgPath: dir, OutDir: outDir})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := `// Code generated by functypes. DO NOT EDIT.
+
+package functypes
+
+type A func() error
+type M func() error
+type Z func() error
+`
+	if got := string(files[filepath.Join(outDir, "sorted_functypes.go")]); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
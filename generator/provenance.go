@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// stringifyProvenance will emit the --provenance comment for the function type with the given name generated from the method, naming the interface and package it was generated from.
+// Methods inherited through an embedded interface are attributed to the interface being converted (the embedder) by default, or to the interface declaring the method (the definer) with --provenance-source=definer.
+func (r *renderer) stringifyProvenance(name string, embedder *types.TypeName, meth *types.Func) string {
+	source := embedder
+	if r.cfg.ProvenanceSource == "definer" {
+		if definer := definingInterface(meth); definer != nil {
+			source = definer
+		}
+	}
+	return fmt.Sprintf("// %s is generated from the %s method of %s in %s.", name, meth.Name(), source.Name(), source.Pkg().Path())
+}
+
+// definingInterface returns the named interface declaring the method, or nil if it was declared in an interface literal.
+// For a method promoted from an embedded interface, this is the embedded interface rather than the one embedding it.
+func definingInterface(meth *types.Func) *types.TypeName {
+	sig, ok := meth.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil
+	}
+	if named, ok := sig.Recv().Type().(*types.Named); ok {
+		return named.Obj()
+	}
+	return nil
+}
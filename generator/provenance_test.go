@@ -0,0 +1,43 @@
+package generator
+
+import "testing"
+
+func TestGenerateProvenance(t *testing.T) {
+	const pkg = "github.com/eaardal/functypes/testdata/embedded"
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{
+			source: "embedder",
+			want: "// Close is generated from the Close method of ReadCloser in " + pkg + ".\ntype Close func() error\n\n" +
+				"// Name is generated from the Name method of ReadCloser in " + pkg + ".\ntype Name func() string\n\n" +
+				"// Read is generated from the Read method of ReadCloser in " + pkg + ".\ntype Read func(p []byte) (n int, err error)\n",
+		},
+		{
+			source: "definer",
+			want: "// Close is generated from the Close method of Closer in " + pkg + ".\ntype Close func() error\n\n" +
+				"// Name is generated from the Name method of ReadCloser in " + pkg + ".\ntype Name func() string\n\n" +
+				"// Read is generated from the Read method of Reader in io.\ntype Read func(p []byte) (n int, err error)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/embedded", Include: "^ReadCloser$", Provenance: true, ProvenanceSource: tt.source})["embedded_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestProvenanceSourceIsValidated(t *testing.T) {
+	_, err := newGenerator(Config{ProvenanceSource: "both"})
+	if want := "--provenance-source must be embedder or definer, got both"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
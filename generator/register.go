@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// registerPackagesCfg is used to load the package of the --register-func when it isn't among the loaded packages and their imports.
+var registerPackagesCfg = &packages.Config{Mode: packages.NeedName | packages.NeedTypes}
+
+// resolveRegisterFunc looks up the function given to --register-func, in the form <import path or package name>.<FuncName>.
+// The function is called once per generated function type with its name and a nil value of the type, so it must have the signature func(string, any).
+func resolveRegisterFunc(pkgs []*packages.Package, name string) (*types.Func, error) {
+	dot := strings.LastIndex(name, ".")
+	if dot <= 0 || dot == len(name)-1 {
+		return nil, fmt.Errorf("--register-func %s must be in the form <import path>.<FuncName>", name)
+	}
+	pkgRef, funcName := name[:dot], name[dot+1:]
+
+	pkg := findOrLoadTypesPackage(pkgs, pkgRef)
+	if pkg == nil {
+		return nil, fmt.Errorf("--register-func %s: failed to load package %s", name, pkgRef)
+	}
+
+	fn, ok := pkg.Scope().Lookup(funcName).(*types.Func)
+	if !ok {
+		return nil, fmt.Errorf("--register-func %s: package %s has no function named %s", name, pkg.Path(), funcName)
+	}
+
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if params.Len() != 2 || sig.Variadic() || !types.Identical(params.At(0).Type(), types.Typ[types.String]) || !isEmptyInterface(params.At(1).Type()) {
+		return nil, fmt.Errorf("--register-func %s must have the signature func(string, any)", name)
+	}
+	return fn, nil
+}
+
+// isEmptyInterface returns true if t is an interface without methods, like any.
+func isEmptyInterface(t types.Type) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	return ok && iface.Empty()
+}
+
+// stringifyInit will emit the --emit-init function, registering each of the registered function types with the --register-func under its name.
+func (r *renderer) stringifyInit() string {
+	register := r.imports.qualifiedName(r.registerFunc.Pkg(), r.registerFunc.Name())
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// init registers the generated function types with %s.\n", register))
+	builder.WriteString("func init() {\n")
+	for _, name := range r.registered {
+		builder.WriteString(fmt.Sprintf("\t%s(%q, %s(nil))\n", register, name, name))
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
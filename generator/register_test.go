@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateInit(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitInit: true, RegisterFunc: "github.com/eaardal/functypes/testdata/registry.Register"})["testdata_functypes.go"]
+
+	want := `// init registers the generated function types with registry.Register.
+func init() {
+	registry.Register("Abc", Abc(nil))
+	registry.Register("Bar", Bar(nil))
+	registry.Register("Foo", Foo(nil))
+}
+`
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to end with\n%s", got, want)
+	}
+}
+
+func TestGenerateInitRegistersEachType(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitInit: true, RegisterFunc: "github.com/eaardal/functypes/testdata/registry.Register"}, map[string]string{
+		"init_test.go": `package functypes
+
+import (
+	"testing"
+
+	"github.com/eaardal/functypes/testdata/registry"
+)
+
+func TestInit(t *testing.T) {
+	if len(registry.Types) != 3 {
+		t.Errorf("registry.Types = %v, want Abc, Bar and Foo", registry.Types)
+	}
+	if _, ok := registry.Types["Abc"].(Abc); !ok {
+		t.Errorf("registry.Types[Abc] = %T, want Abc", registry.Types["Abc"])
+	}
+	if _, ok := registry.Types["Bar"].(Bar); !ok {
+		t.Errorf("registry.Types[Bar] = %T, want Bar", registry.Types["Bar"])
+	}
+	if _, ok := registry.Types["Foo"].(Foo); !ok {
+		t.Errorf("registry.Types[Foo] = %T, want Foo", registry.Types["Foo"])
+	}
+}
+`,
+	})
+}
+
+func TestRegisterFuncIsValidated(t *testing.T) {
+	g, err := newGenerator(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := g.loadPackages("../testdata/registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Register", want: "--register-func Register must be in the form <import path>.<FuncName>"},
+		{name: "github.com/eaardal/functypes/testdata/registry.Missing", want: "--register-func github.com/eaardal/functypes/testdata/registry.Missing: package github.com/eaardal/functypes/testdata/registry has no function named Missing"},
+		{name: "strings.ToUpper", want: "--register-func strings.ToUpper must have the signature func(string, any)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveRegisterFunc(pkgs, tt.name)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("resolveRegisterFunc() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmitInitRequiresRegisterFunc(t *testing.T) {
+	_, err := newGenerator(Config{EmitInit: true})
+	if want := "--emit-init requires --register-func"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// typeString returns the Go source representation of the given type, as used in the generated code.
+func (r *renderer) typeString(t types.Type) string {
+	builder := &strings.Builder{}
+	r.writeType(builder, t)
+	return builder.String()
+}
+
+// writeType writes the Go source representation of the given type to the builder.
+// It mirrors types.TypeString, but gives us control over how each kind of type is rendered, such as how the empty interface is spelled (see --empty-interface).
+func (r *renderer) writeType(builder *strings.Builder, t types.Type) {
+	if replacement := r.replacementFor(t); replacement != nil {
+		r.writeType(builder, replacement)
+		return
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		// unsafe.Pointer is a basic type too, but declared by the unsafe package rather than predeclared.
+		if t.Kind() == types.UnsafePointer {
+			builder.WriteString(r.imports.qualifiedName(types.Unsafe, t.Name()))
+			return
+		}
+		builder.WriteString(r.builtinName(t.Name()))
+	case *types.Pointer:
+		builder.WriteString("*")
+		r.writeType(builder, t.Elem())
+	case *types.Slice:
+		builder.WriteString("[]")
+		r.writeType(builder, t.Elem())
+	case *types.Array:
+		builder.WriteString(fmt.Sprintf("[%d]", t.Len()))
+		r.writeType(builder, t.Elem())
+	case *types.Map:
+		builder.WriteString("map[")
+		r.writeType(builder, t.Key())
+		builder.WriteString("]")
+		r.writeType(builder, t.Elem())
+	case *types.Chan:
+		r.writeChan(builder, t)
+	case *types.Signature:
+		builder.WriteString("func")
+		r.writeSignature(builder, t)
+	case *types.Struct:
+		r.writeStruct(builder, t)
+	case *types.Interface:
+		r.writeInterface(builder, t)
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			if i > 0 {
+				builder.WriteString(" | ")
+			}
+			if t.Term(i).Tilde() {
+				builder.WriteString("~")
+			}
+			r.writeType(builder, t.Term(i).Type())
+		}
+	case *types.Tuple:
+		r.writeTuple(builder, t, false)
+	case *types.Named:
+		// Named types are always referenced by name, never by their underlying type, so a named function type like http.HandlerFunc stays http.HandlerFunc.
+		if r.inlines(t) {
+			builder.WriteString(t.Obj().Name())
+			return
+		}
+		r.writeTypeName(builder, t.Obj())
+		r.writeTypeArgs(builder, t.TypeArgs())
+	case *types.Alias:
+		// The universe any is an alias of the empty interface, so it's spelled according to --empty-interface as well.
+		if t.Obj().Pkg() == nil && t.Obj().Name() == "any" {
+			builder.WriteString(r.emptyInterface())
+			return
+		}
+		// Aliases are kept by default, and only resolved to the type they denote with --expand-aliases.
+		if r.cfg.ExpandAliases {
+			r.writeType(builder, types.Unalias(t))
+			return
+		}
+		r.writeTypeName(builder, t.Obj())
+		r.writeTypeArgs(builder, t.TypeArgs())
+	case *types.TypeParam:
+		builder.WriteString(t.Obj().Name())
+	default:
+		builder.WriteString(types.TypeString(t, r.imports.qualify))
+	}
+}
+
+// writeTypeName writes the name of a named type or alias, qualified with the name of its package unless it's a predeclared type like error or a type of the local package (see --qualify-relative-to).
+func (r *renderer) writeTypeName(builder *strings.Builder, obj *types.TypeName) {
+	if obj.Pkg() == nil {
+		builder.WriteString(obj.Name())
+		return
+	}
+	builder.WriteString(r.imports.qualifiedName(obj.Pkg(), obj.Name()))
+}
+
+// writeTypeArgs writes the type arguments of an instantiated generic type, such as [string, int].
+func (r *renderer) writeTypeArgs(builder *strings.Builder, typeArgs *types.TypeList) {
+	if typeArgs.Len() == 0 {
+		return
+	}
+
+	builder.WriteString("[")
+	for i := 0; i < typeArgs.Len(); i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		r.writeType(builder, typeArgs.At(i))
+	}
+	builder.WriteString("]")
+}
+
+// writeChan writes a channel type, wrapping the element type in parentheses where it would otherwise be ambiguous, as in chan (<-chan int).
+func (r *renderer) writeChan(builder *strings.Builder, t *types.Chan) {
+	parens := false
+	switch t.Dir() {
+	case types.SendRecv:
+		builder.WriteString("chan ")
+		if elem, ok := t.Elem().(*types.Chan); ok && elem.Dir() == types.RecvOnly {
+			parens = true
+		}
+	case types.SendOnly:
+		builder.WriteString("chan<- ")
+	case types.RecvOnly:
+		builder.WriteString("<-chan ")
+	}
+
+	if parens {
+		builder.WriteString("(")
+	}
+	r.writeType(builder, t.Elem())
+	if parens {
+		builder.WriteString(")")
+	}
+}
+
+// writeSignature writes the parameters and results of a function signature, without the leading func keyword.
+func (r *renderer) writeSignature(builder *strings.Builder, sig *types.Signature) {
+	r.writeTuple(builder, sig.Params(), sig.Variadic())
+
+	results := sig.Results()
+	if results.Len() == 0 {
+		return
+	}
+
+	builder.WriteString(" ")
+	if results.Len() == 1 && !r.writesNames(results) {
+		r.writeType(builder, results.At(0).Type())
+		return
+	}
+	r.writeTuple(builder, results, false)
+}
+
+// writeTuple writes a parenthesized parameter or result list. If variadic is true, the last entry is written as ...T rather than []T.
+// Names are dropped with --no-param-names or when they're all blank, so the list only has the types (see writesNames).
+func (r *renderer) writeTuple(builder *strings.Builder, tuple *types.Tuple, variadic bool) {
+	names := r.writesNames(tuple)
+
+	builder.WriteString("(")
+	for i := 0; i < tuple.Len(); i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+
+		v := tuple.At(i)
+		if names {
+			builder.WriteString(v.Name() + " ")
+		}
+
+		if variadic && i == tuple.Len()-1 {
+			if slice, ok := v.Type().(*types.Slice); ok {
+				builder.WriteString("...")
+				r.writeType(builder, slice.Elem())
+				continue
+			}
+		}
+		r.writeType(builder, v.Type())
+	}
+	builder.WriteString(")")
+}
+
+// writesNames returns true if the names of the tuple's variables are written.
+// Go requires either all or none of the entries to be named, which go/types guarantees, so this only decides between the two: names are dropped with --no-param-names, and when they're all blank like in (_ int, _ error), which is the same as (int, error). A mix of blank and other names like (_ int, err error) is kept.
+func (r *renderer) writesNames(tuple *types.Tuple) bool {
+	if r.cfg.NoParamNames {
+		return false
+	}
+	for i := 0; i < tuple.Len(); i++ {
+		if name := tuple.At(i).Name(); name != "" && name != "_" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeStruct writes an inline struct type, including embedded fields and struct tags.
+func (r *renderer) writeStruct(builder *strings.Builder, t *types.Struct) {
+	builder.WriteString("struct{")
+	for i := 0; i < t.NumFields(); i++ {
+		if i > 0 {
+			builder.WriteString("; ")
+		}
+
+		field := t.Field(i)
+		if !field.Embedded() {
+			builder.WriteString(field.Name() + " ")
+		}
+		r.writeType(builder, field.Type())
+
+		if tag := t.Tag(i); tag != "" {
+			builder.WriteString(" " + strconv.Quote(tag))
+		}
+	}
+	builder.WriteString("}")
+}
+
+// writeInterface writes an inline interface type. The empty interface is written as any or interface{} depending on --empty-interface.
+func (r *renderer) writeInterface(builder *strings.Builder, t *types.Interface) {
+	if t.NumExplicitMethods() == 0 && t.NumEmbeddeds() == 0 {
+		builder.WriteString(r.emptyInterface())
+		return
+	}
+
+	// Implicit interfaces are constraints written without the interface keyword, like the ~int | ~string in [T ~int | ~string].
+	if t.IsImplicit() && t.NumEmbeddeds() == 1 && t.NumExplicitMethods() == 0 {
+		r.writeType(builder, t.EmbeddedType(0))
+		return
+	}
+
+	builder.WriteString("interface{")
+	for i := 0; i < t.NumExplicitMethods(); i++ {
+		if i > 0 {
+			builder.WriteString("; ")
+		}
+		meth := t.ExplicitMethod(i)
+		builder.WriteString(meth.Name())
+		r.writeSignature(builder, meth.Type().(*types.Signature))
+	}
+	for i := 0; i < t.NumEmbeddeds(); i++ {
+		if i > 0 || t.NumExplicitMethods() > 0 {
+			builder.WriteString("; ")
+		}
+		r.writeType(builder, t.EmbeddedType(i))
+	}
+	builder.WriteString("}")
+}
+
+// emptyInterface returns the spelling of the empty interface chosen with --empty-interface.
+func (r *renderer) emptyInterface() string {
+	if r.cfg.EmptyInterface == "interface{}" {
+		return "interface{}"
+	}
+	return r.builtinName("any")
+}
@@ -0,0 +1,233 @@
+package generator
+
+import "testing"
+
+func TestGenerateEmptyInterface(t *testing.T) {
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{
+			style: "any",
+			want:  "type Each func(fn func(key string, value any) bool)\ntype Get func(key string) any\ntype Put func(key string, value any)\n",
+		},
+		{
+			style: "interface{}",
+			want:  "type Each func(fn func(key string, value interface{}) bool)\ntype Get func(key string) interface{}\ntype Put func(key string, value interface{})\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			// AnyStore spells the empty interface both ways.
+			got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^AnyStore$", EmptyInterface: tt.style})["testdata_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateNamedFuncTypesFromOtherPackages(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/routes"})["routes_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"net/http"
+	"time"
+)
+
+type Handler func(pattern string) http.HandlerFunc
+type Routes func() map[string]http.HandlerFunc
+type Wrap func(next http.Handler, after func(time.Duration)) http.HandlerFunc
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateExpandAliases(t *testing.T) {
+	tests := []struct {
+		name          string
+		expandAliases bool
+		want          string
+	}{
+		{
+			name: "preserved",
+			want: "import (\n\t\"github.com/eaardal/functypes/testdata/aliases\"\n)\n\ntype Add func(delta aliases.MyInt) aliases.MyInt\ntype Wait func(timeout aliases.Timeout) error\ntype Keys func(set aliases.Set[string]) []string\n",
+		},
+		{
+			name:          "expanded",
+			expandAliases: true,
+			want:          "import (\n\t\"time\"\n)\n\ntype Add func(delta int) int\ntype Wait func(timeout time.Duration) error\ntype Keys func(set map[string]bool) []string\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/aliases", ExpandAliases: tt.expandAliases, Validate: true})["aliases_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateNoParamNames(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^(MyInterface|Reader)$", NoParamNames: true})["testdata_functypes.go"]
+
+	// The parameter and result names are dropped, while Foo stays variadic.
+	want := generatedHeader + `
+
+package functypes
+
+type Abc func() (string, error)
+type Bar func(string) error
+type Foo func(string, int, ...string)
+type Read func([]byte) (int, error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateBlankNames(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/blanks"})["blanks_functypes.go"]
+
+	// Blank names mixed with other names are kept, since a list must name all or none of its entries. Lists of only blank names are rendered unnamed.
+	want := generatedHeader + `
+
+package functypes
+
+type Close func() error
+type Scan func(_ []byte, n int) (_ int, err error)
+type Skip func(int) (int, error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	goTestGenerated(t, Config{PkgPath: "../testdata/blanks"}, nil)
+}
+
+func TestGenerateMultiLevelPointers(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/pointers"})["pointers_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"net/http"
+	"net/url"
+)
+
+type Request func() **http.Request
+type Swap func(req **http.Request, headers *[]*http.Header) **[]*url.Values
+type URL func(raw string) (u ***url.URL, err error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateUnsafePointers(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/unsafeptr", Validate: true})["unsafeptr_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"unsafe"
+)
+
+type Addr func() unsafe.Pointer
+type Store func(p unsafe.Pointer, n int) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateInlineInterfaces(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/inlineiface"})["inlineiface_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type Run func(job interface {
+	Do(ctx context.Context) error
+}) error
+type Serve func(h interface {
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+	io.Closer
+}) (interface{ Done() <-chan struct{} }, error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The inline interfaces are identical to the ones of the methods, so the methods are assignable to the function types.
+	goTestGenerated(t, Config{PkgPath: "../testdata/inlineiface"}, map[string]string{
+		"assign_test.go": `package functypes
+
+import "github.com/eaardal/functypes/testdata/inlineiface"
+
+func assign(r inlineiface.Runner) {
+	var _ Run = r.Run
+	var _ Serve = r.Serve
+}
+`,
+	})
+}
+
+func TestGenerateTypeAlias(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^(MyInterface|Reader)$", TypeAlias: true})["testdata_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type Abc = func() (string, error)
+type Bar = func(a string) error
+type Foo = func(a string, b int, c ...string)
+type Read = func(p []byte) (n int, err error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	// A value of another named function type with the same signature is assignable without a conversion.
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^Reader$", TypeAlias: true}, map[string]string{
+		"alias_test.go": `package functypes
+
+type readFunc func(p []byte) (n int, err error)
+
+func assign(f readFunc) {
+	var _ Read = f
+}
+`,
+	})
+}
+
+func TestTypeAliasCantHaveNilChecks(t *testing.T) {
+	_, err := newGenerator(Config{TypeAlias: true, EmitNilChecks: true})
+	if want := "--type-alias can't be used with --emit-nil-checks, since methods can't be declared on an alias of a function signature"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typeReplacement is a single old=new entry of --replace-type.
+type typeReplacement struct {
+	old, new string
+}
+
+// parseTypeReplacements parses the comma separated old=new entries of --replace-type, where both sides are a type in the form [*]<import path>.<TypeName>.
+func parseTypeReplacements(s string) ([]typeReplacement, error) {
+	var replacements []typeReplacement
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		oldRef, newRef, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(oldRef) == "" || strings.TrimSpace(newRef) == "" {
+			return nil, fmt.Errorf("--replace-type entry %q must be in the form <old type>=<new type>", entry)
+		}
+		replacements = append(replacements, typeReplacement{old: strings.TrimSpace(oldRef), new: strings.TrimSpace(newRef)})
+	}
+	return replacements, nil
+}
+
+// resolveTypeReplacements looks up the types of the --replace-type entries, and returns the new types by the key of the old type (see replacementKey).
+// The types must be assignable in at least one direction, like a concrete type and an interface it implements, so the generated function types stay usable with the values of the source interfaces.
+func resolveTypeReplacements(pkgs []*packages.Package, replacements []typeReplacement) (map[string]types.Type, error) {
+	resolved := make(map[string]types.Type)
+	for _, replacement := range replacements {
+		oldType, err := resolveTypeRef(pkgs, replacement.old)
+		if err != nil {
+			return nil, err
+		}
+		newType, err := resolveTypeRef(pkgs, replacement.new)
+		if err != nil {
+			return nil, err
+		}
+
+		if !types.AssignableTo(oldType, newType) && !types.AssignableTo(newType, oldType) {
+			return nil, fmt.Errorf("--replace-type %s=%s: the types are not assignable to each other", replacement.old, replacement.new)
+		}
+
+		key, _ := replacementKey(oldType)
+		resolved[key] = newType
+	}
+	return resolved, nil
+}
+
+// resolveTypeRef looks up a --replace-type type in the form [*]<import path>.<TypeName>, loading its package if it isn't among the loaded packages and their imports.
+func resolveTypeRef(pkgs []*packages.Package, ref string) (types.Type, error) {
+	name, pointer := strings.CutPrefix(ref, "*")
+
+	dot := strings.LastIndex(name, ".")
+	if dot <= 0 || dot == len(name)-1 {
+		return nil, fmt.Errorf("--replace-type %s must be in the form [*]<import path>.<TypeName>", ref)
+	}
+	pkgRef, typeName := name[:dot], name[dot+1:]
+
+	pkg := findOrLoadTypesPackage(pkgs, pkgRef)
+	if pkg == nil {
+		return nil, fmt.Errorf("--replace-type %s: failed to load package %s", ref, pkgRef)
+	}
+
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("--replace-type %s: package %s has no type named %s", ref, pkg.Path(), typeName)
+	}
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("--replace-type %s: generic types can't be replaced", ref)
+	}
+
+	if pointer {
+		return types.NewPointer(obj.Type()), nil
+	}
+	return obj.Type(), nil
+}
+
+// replacementKey returns the key identifying the type among the --replace-type replacements: the type's import path and name, prefixed with * for a pointer to it.
+// Returns false for any other type than a non-generic named type or a pointer to one.
+func replacementKey(t types.Type) (string, bool) {
+	prefix := ""
+	if pointer, ok := t.(*types.Pointer); ok {
+		prefix, t = "*", pointer.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.TypeArgs().Len() > 0 {
+		return "", false
+	}
+	return prefix + named.Obj().Pkg().Path() + "." + named.Obj().Name(), true
+}
+
+// replacementFor returns the type to render instead of the given one because of --replace-type, or nil if it isn't replaced.
+// Every replacement is recorded, so the adapter of an interface referencing replaced types can be skipped (see processInterfacesInScope).
+func (r *renderer) replacementFor(t types.Type) types.Type {
+	if len(r.replacements) == 0 {
+		return nil
+	}
+
+	key, ok := replacementKey(t)
+	if !ok {
+		return nil
+	}
+	replacement := r.replacements[key]
+	if replacement != nil {
+		r.replacedTypes = true
+	}
+	return replacement
+}
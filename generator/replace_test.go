@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGenerateReplaceType(t *testing.T) {
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: "../testdata/replace", EmitAdapter: true, ReplaceType: "*github.com/eaardal/functypes/testdata/widen.File=github.com/eaardal/functypes/testdata/widen.Named"})["replace_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/widen"
+)
+
+type Get func(name string) (widen.Named, error)
+type Put func(f widen.Named) error
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantWarning := "skipping adapter for Store because its function types use types replaced with --replace-type"
+	if !slices.Contains(logged(), wantWarning) {
+		t.Errorf("warnings = %q, want %q", logged(), wantWarning)
+	}
+}
+
+func TestReplaceTypeIsValidated(t *testing.T) {
+	tests := []struct {
+		replaceType string
+		want        string
+	}{
+		{replaceType: "widen.File", want: `--replace-type entry "widen.File" must be in the form <old type>=<new type>`},
+		{replaceType: "File=widen.Named", want: "--replace-type File must be in the form [*]<import path>.<TypeName>"},
+		{replaceType: "github.com/eaardal/functypes/testdata/widen.Missing=github.com/eaardal/functypes/testdata/widen.Named", want: "--replace-type github.com/eaardal/functypes/testdata/widen.Missing: package github.com/eaardal/functypes/testdata/widen has no type named Missing"},
+		{replaceType: "github.com/eaardal/functypes/testdata/widen.Options=github.com/eaardal/functypes/testdata/widen.Named", want: "--replace-type github.com/eaardal/functypes/testdata/widen.Options=github.com/eaardal/functypes/testdata/widen.Named: the types are not assignable to each other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.replaceType, func(t *testing.T) {
+			err := Generate(Config{PkgPath: "../testdata/replace", OutDir: t.TempDir(), ReplaceType: tt.replaceType})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderIsReproducible(t *testing.T) {
+	cfg := Config{PkgPath: "../testdata", OutDir: "functypes", EmitAdapter: true, EmitMust: true, EmitStubs: true, EmitTest: true, Provenance: true}
+
+	first, err := Render(cfg)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	second, err := Render(cfg)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if len(first) != len(second) {
+		t.Fatalf("Render() rendered %d files, then %d", len(first), len(second))
+	}
+	for path, content := range first {
+		if !bytes.Equal(content, second[path]) {
+			t.Errorf("Render() rendered %s as\n%s\nthen as\n%s", path, content, second[path])
+		}
+	}
+}
+
+func TestRenderIsIndependentOfFileOrder(t *testing.T) {
+	sources := []string{
+		"package shuffled\n\ntype Reader interface {\n\tRead(p []byte) (n int, err error)\n}\n",
+		"package shuffled\n\ntype Writer interface {\n\tWrite(p []byte) (n int, err error)\n\tFlush() error\n}\n",
+		"package shuffled\n\ntype Closer interface {\n\tClose() error\n}\n",
+	}
+
+	dir, err := os.MkdirTemp("../testdata", "shuffled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	// The same package, with the declarations spread over the files in a different order.
+	render := func(order []int) []byte {
+		for i, source := range order {
+			name := filepath.Join(dir, string(rune('a'+i))+".go")
+			if err := os.WriteFile(name, []byte(sources[source]), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		files, err := Render(Config{PkgPath: dir, OutDir: "functypes", EmitAdapter: true, Provenance: true})
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return files[filepath.Join("functypes", "shuffled_functypes.go")]
+	}
+
+	want := render([]int{0, 1, 2})
+	if len(want) == 0 {
+		t.Fatalf("Render() rendered no shuffled_functypes.go")
+	}
+	for _, order := range [][]int{{2, 1, 0}, {1, 2, 0}} {
+		if got := render(order); !bytes.Equal(got, want) {
+			t.Errorf("Render() with the files in order %v =\n%s\nwant\n%s", order, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// route sends the function types of the interfaces matching pattern to their own output directory, see --route.
+type route struct {
+	// pattern is a glob pattern matched against interface names (see path.Match for the pattern syntax).
+	pattern string
+	// dir is the output directory for the matching interfaces.
+	dir string
+}
+
+// parseRoutes parses a --route value, which is a semicolon separated list of <pattern>=<dir> entries like Repo*=./repos;Svc*=./services.
+func parseRoutes(spec string) ([]route, error) {
+	var routes []route
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, dir, ok := strings.Cut(entry, "=")
+		pattern, dir = strings.TrimSpace(pattern), strings.TrimSpace(dir)
+		if !ok || pattern == "" || dir == "" {
+			return nil, fmt.Errorf("--route entry %q must be in the form <pattern>=<dir>", entry)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("--route entry %q has an invalid pattern: %v", entry, err)
+		}
+
+		routes = append(routes, route{pattern: pattern, dir: dir})
+	}
+	return routes, nil
+}
+
+// outDirFor returns the output directory for the function types of the named interface: the directory of the first route matching the name, or --out-dir if none does.
+func (g *generator) outDirFor(ifaceName string) string {
+	for _, rt := range g.routes {
+		if ok, _ := path.Match(rt.pattern, ifaceName); ok {
+			return rt.dir
+		}
+	}
+	return g.cfg.OutDir
+}
+
+// outDirs returns every output directory used by this run: --out-dir followed by the directories of the routes, without duplicates.
+func (g *generator) outDirs() []string {
+	dirs := []string{g.cfg.OutDir}
+	seen := map[string]bool{g.cfg.OutDir: true}
+	for _, rt := range g.routes {
+		if !seen[rt.dir] {
+			seen[rt.dir] = true
+			dirs = append(dirs, rt.dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRoute(t *testing.T) {
+	outDir := t.TempDir()
+	readers := filepath.Join(outDir, "readers")
+	mine := filepath.Join(outDir, "mine")
+
+	generateFiles(t, Config{PkgPath: "../testdata", OutDir: outDir, Include: "^(Reader|MyInterface|OtherInterface)$", Route: "Read*=" + readers + "; My*=" + mine})
+
+	const header = generatedHeader + "\n\npackage functypes\n\n"
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{
+			dir:  readers,
+			want: header + "type Read func(p []byte) (n int, err error)\n",
+		},
+		{
+			dir:  mine,
+			want: header + "type Abc func() (string, error)\ntype Bar func(a string) error\ntype Foo func(a string, b int, c ...string)\n",
+		},
+		{
+			// Interfaces matching no route stay in --out-dir.
+			dir:  outDir,
+			want: header + "type Aaa func()\n",
+		},
+	}
+
+	for _, tt := range tests {
+		files := readFiles(t, tt.dir)
+		if got := files["testdata_functypes.go"]; got != tt.want {
+			t.Errorf("Generate() wrote to %s\n%s\nwant\n%s", tt.dir, got, tt.want)
+		}
+		// Each directory keeps its own manifest, so --clean finds the routed files too.
+		if manifest, err := readManifest(tt.dir); err != nil || !reflect.DeepEqual(manifest, []string{"testdata_functypes.go"}) {
+			t.Errorf("readManifest(%s) = %v, %v, want [testdata_functypes.go]", tt.dir, manifest, err)
+		}
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []route
+		wantErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "entries",
+			spec: "Repo*=./repos; Svc*=./services;",
+			want: []route{{pattern: "Repo*", dir: "./repos"}, {pattern: "Svc*", dir: "./services"}},
+		},
+		{
+			name:    "entry without a directory",
+			spec:    "Repo*=",
+			wantErr: `--route entry "Repo*=" must be in the form <pattern>=<dir>`,
+		},
+		{
+			name:    "invalid pattern",
+			spec:    "Repo[=./repos",
+			wantErr: `--route entry "Repo[=./repos" has an invalid pattern: syntax error in pattern`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRoutes(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("parseRoutes() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRoutes() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
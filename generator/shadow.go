@@ -0,0 +1,87 @@
+package generator
+
+import "go/types"
+
+// builtinSpellings are the other spellings of the predeclared types which have one, for referencing them from a package declaring its own type of the same name.
+var builtinSpellings = map[string]string{
+	"byte": "uint8",
+	"rune": "int32",
+	"any":  "interface{}",
+}
+
+// shadowsBuiltin returns true if the --qualify-relative-to package the generated files are part of declares its own identifier with the name of the predeclared type.
+// In its files the name refers to the package's own declaration, so the predeclared type has to be spelled differently (see builtinSpellings), if that's possible at all.
+func (r *renderer) shadowsBuiltin(name string) bool {
+	return r.localPkg != nil && r.localPkg.Scope().Lookup(name) != nil
+}
+
+// builtinName returns how the predeclared type with the given name is spelled in the generated code: by its name, unless the local package shadows it (see shadowsBuiltin).
+func (r *renderer) builtinName(name string) string {
+	if spelling, ok := builtinSpellings[name]; ok && r.shadowsBuiltin(name) {
+		return spelling
+	}
+	return name
+}
+
+// shadowedBuiltin returns the name of a predeclared type referenced by the type which the local package shadows and which has no other spelling, like string or error. Such a type can't be referenced from the generated code, since its name refers to the package's own declaration. It returns an empty string if there's none.
+// Types are told apart by object identity: only the predeclared objects are considered, never the package's own types of the same name.
+func (r *renderer) shadowedBuiltin(t types.Type) string {
+	if r.localPkg == nil {
+		return ""
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		if _, ok := builtinSpellings[t.Name()]; !ok && t.Kind() != types.UnsafePointer && r.shadowsBuiltin(t.Name()) {
+			return t.Name()
+		}
+	case *types.Pointer:
+		return r.shadowedBuiltin(t.Elem())
+	case *types.Slice:
+		return r.shadowedBuiltin(t.Elem())
+	case *types.Array:
+		return r.shadowedBuiltin(t.Elem())
+	case *types.Chan:
+		return r.shadowedBuiltin(t.Elem())
+	case *types.Map:
+		if name := r.shadowedBuiltin(t.Key()); name != "" {
+			return name
+		}
+		return r.shadowedBuiltin(t.Elem())
+	case *types.Signature:
+		if name := r.shadowedBuiltin(t.Params()); name != "" {
+			return name
+		}
+		return r.shadowedBuiltin(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if name := r.shadowedBuiltin(t.At(i).Type()); name != "" {
+				return name
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if name := r.shadowedBuiltin(t.Field(i).Type()); name != "" {
+				return name
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			if name := r.shadowedBuiltin(t.Method(i).Type()); name != "" {
+				return name
+			}
+		}
+	case *types.Named:
+		// The predeclared error is the only named type without a package, the others are only checked for their type arguments.
+		if t.Obj().Pkg() == nil && r.shadowsBuiltin(t.Obj().Name()) {
+			return t.Obj().Name()
+		}
+		typeArgs := t.TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			if name := r.shadowedBuiltin(typeArgs.At(i)); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGenerateSpellsShadowedBuiltinsDifferently(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/shadow", QualifyRelativeTo: "github.com/eaardal/functypes/testdata/shadow"})["shadow_functypes.go"]
+
+	want := generatedHeader + `
+
+package shadow
+
+type Encode func(b byte) error
+type Write func(p []uint8) (n int, err error)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateSkipsMethodsUsingShadowedBuiltins(t *testing.T) {
+	dir, err := os.MkdirTemp("../testdata", "shadowstring")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	name := filepath.Base(dir)
+	// The String method of the embedded fmt.Stringer returns the predeclared string, which has no other spelling.
+	source := "package " + name + `
+
+import "fmt"
+
+type string []rune
+
+type Namer interface {
+	fmt.Stringer
+	Label(s string) int
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, name+".go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: dir, QualifyRelativeTo: "github.com/eaardal/functypes/testdata/" + name})[name+"_functypes.go"]
+
+	want := generatedHeader + "\n\npackage " + name + "\n\ntype Label func(s string) int\n"
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantWarnings := []string{"skipping method String of Namer because its signature uses the predeclared string, which github.com/eaardal/functypes/testdata/" + name + " shadows with its own string"}
+	if got := logged(); !slices.Equal(got, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", got, wantWarnings)
+	}
+}
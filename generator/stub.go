@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// errNotImplementedName is the name of the sentinel error returned by stubs with --stub-error=sentinel.
+const errNotImplementedName = "ErrNotImplemented"
+
+// stringifyStub will take an interface method and emit a Stub<Method> function returning an implementation of the function type which ignores its parameters and returns zero values.
+// Error results are nil, or the ErrNotImplemented sentinel with --stub-error=sentinel so calls to unimplemented stubs can be detected at runtime. Only results of the builtin error type get the sentinel, other error types (see --error-type) are nil either way.
+func (r *renderer) stringifyStub(meth *types.Func, typeParams typeParamLists) string {
+	sig, ok := meth.Type().Underlying().(*types.Signature)
+	if !ok {
+		return ""
+	}
+	// The stub has to match the function type, whose parameters may be widened with --widen-params.
+	sig = r.widenSignature(sig)
+
+	reserved := make(map[string]bool)
+	params := r.forwardParams(sig, reserved)
+
+	var resultTypes, resultValues, zeroDecls []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		result := sig.Results().At(i).Type()
+		resultTypes = append(resultTypes, r.typeString(result))
+
+		if types.Identical(result, builtinError) || r.isErrorType(result) {
+			if r.cfg.StubError == "sentinel" && types.Identical(result, builtinError) {
+				r.usesErrNotImplemented = true
+				resultValues = append(resultValues, errNotImplementedName)
+			} else {
+				resultValues = append(resultValues, "nil")
+			}
+			continue
+		}
+
+		name := uniqueName(fmt.Sprintf("r%d", i), reserved)
+		reserved[name] = true
+		zeroDecls = append(zeroDecls, fmt.Sprintf("\t\tvar %s %s\n", name, resultTypes[i]))
+		resultValues = append(resultValues, name)
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// Stub%s returns a %s which does nothing and returns zero values.\n", meth.Name(), meth.Name()))
+	builder.WriteString(fmt.Sprintf("func Stub%s%s() %s%s {\n", meth.Name(), typeParams.decl, meth.Name(), typeParams.use))
+	builder.WriteString(fmt.Sprintf("\treturn func(%s)%s {\n", params.decl, resultList(resultTypes)))
+	builder.WriteString(strings.Join(zeroDecls, ""))
+	if len(resultValues) > 0 {
+		builder.WriteString(fmt.Sprintf("\t\treturn %s\n", strings.Join(resultValues, ", ")))
+	}
+	builder.WriteString("\t}\n}")
+	return builder.String()
+}
+
+// stringifyErrNotImplemented will emit the declaration of the ErrNotImplemented sentinel returned by stubs with --stub-error=sentinel. It's emitted once per generated file.
+func (r *renderer) stringifyErrNotImplemented() string {
+	errorsName := r.imports.qualify(types.NewPackage("errors", "errors"))
+	return fmt.Sprintf("// %s is returned as the error by the stubs, so calls to unimplemented stubs can be detected with errors.Is.\nvar %s = %s.New(\"not implemented\")", errNotImplementedName, errNotImplementedName, errorsName)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateStubs(t *testing.T) {
+	tests := []struct {
+		stubError string
+		want      string
+	}{
+		{
+			stubError: "nil",
+			want:      "func StubAbc() Abc {\n\treturn func() (string, error) {\n\t\tvar r0 string\n\t\treturn r0, nil\n\t}\n}\n",
+		},
+		{
+			stubError: "sentinel",
+			want:      "func StubAbc() Abc {\n\treturn func() (string, error) {\n\t\tvar r0 string\n\t\treturn r0, ErrNotImplemented\n\t}\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stubError, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitStubs: true, StubError: tt.stubError})["testdata_functypes.go"]
+
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, tt.want)
+			}
+			// The sentinel is declared once, and only if a stub returns it.
+			if n := strings.Count(got, "var ErrNotImplemented = errors.New"); n != map[string]int{"nil": 0, "sentinel": 1}[tt.stubError] {
+				t.Errorf("Generate() =\n%s\ndeclares ErrNotImplemented %d times", got, n)
+			}
+		})
+	}
+}
+
+func TestGenerateStubsReturnTheSentinel(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitStubs: true, StubError: "sentinel"}, map[string]string{
+		"stub_test.go": `package functypes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStubs(t *testing.T) {
+	if _, err := StubAbc()(); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("StubAbc()() error = %v, want ErrNotImplemented", err)
+	}
+	if err := StubBar()("a"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("StubBar()() error = %v, want ErrNotImplemented", err)
+	}
+	StubFoo()("a", 1)
+}
+`,
+	})
+}
+
+func TestStubErrorIsValidated(t *testing.T) {
+	_, err := newGenerator(Config{StubError: "panic"})
+	if want := "--stub-error must be nil or sentinel, got panic"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
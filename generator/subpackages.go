@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/go/packages"
+)
+
+// subpackageName returns the name of the subpackage the function types of the named interface are written to with --subpackages: the interface name in lower case, like reader for Reader.
+// Names which are keywords, like type for an interface named Type, get a pkg suffix.
+func subpackageName(ifaceName string) string {
+	name := strings.ToLower(ifaceName)
+	if token.IsKeyword(name) {
+		name += "pkg"
+	}
+	return name
+}
+
+// subpackageRoutes returns a route per interface declared in the packages, sending its function types to its subpackage below the output directory (see --subpackages).
+// Interfaces which aren't rendered in the end don't get a subpackage, just like routes nothing is rendered to don't get a file.
+func subpackageRoutes(pkgs []*packages.Package, outDir string) []route {
+	var routes []route
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		for _, obj := range sortedObjects(pkg.Types.Scope()) {
+			if _, ok := obj.(*types.TypeName); !ok || !types.IsInterface(obj.Type()) || seen[obj.Name()] {
+				continue
+			}
+			seen[obj.Name()] = true
+			routes = append(routes, route{pattern: obj.Name(), dir: path.Join(outDir, subpackageName(obj.Name()))})
+		}
+	}
+	return routes
+}
+
+// subpackageDirs returns the subdirectories of the output directory with a manifest, which are the subpackages generated by previous runs with --subpackages. Which interfaces they were generated for isn't known without loading the source, which --clean doesn't do.
+func subpackageDirs(outDir string) []string {
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		dir := path.Join(outDir, entry.Name())
+		if _, err := os.Stat(path.Join(dir, manifestFileName)); entry.IsDir() && err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// packageNameFor returns the name of the package the files generated into the output directory belong to. That's the subpackage's name for the subpackages of --subpackages, and packageName otherwise.
+func (g *generator) packageNameFor(outDir string) string {
+	if g.cfg.Subpackages && outDir != g.cfg.OutDir {
+		return path.Base(outDir)
+	}
+	return g.packageName()
+}
+
+// generateAggregator renders <pkgName>_functypes.go in the output directory for --subpackages, re-exporting the types generated into the subpackages for the package with type aliases, like type Read = reader.Read.
+// Functions like the Must<Name> wrappers can't be re-exported without changing what they are, and generic types can't be aliased before Go 1.24, so those are only available from their subpackage.
+func (g *generator) generateAggregator(pkgs []*packages.Package, pkgName string) error {
+	imports := newImportSet()
+	body := &strings.Builder{}
+	reexported := make(map[string]string)
+
+	fileName := fmt.Sprintf("%s_functypes.go", pkgName)
+	for _, outDir := range g.outDirs() {
+		subFilePath := path.Join(outDir, fileName)
+		src, ok := g.files[subFilePath]
+		if outDir == g.cfg.OutDir || !ok {
+			continue
+		}
+
+		importPath, ok := outDirImportPath(pkgs, outDir)
+		if !ok {
+			return fmt.Errorf("--subpackages: failed to tell the import path of %s, which the aggregator in %s imports", outDir, g.cfg.OutDir)
+		}
+
+		names, err := exportedTypeNames(subFilePath, src)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		subpackage := path.Base(outDir)
+		exported := false
+		for _, name := range names {
+			// Interfaces sharing a method, like Close, each have its function type in their subpackage, but the aggregator can only alias one of them.
+			if from, ok := reexported[name]; ok {
+				logrus.Warnf("not re-exporting %s from %s in the aggregator because it's re-exported from %s already", name, subpackage, from)
+				continue
+			}
+			reexported[name] = subpackage
+			body.WriteString(fmt.Sprintf("type %s = %s.%s\n", name, subpackage, name))
+			exported = true
+		}
+		if exported {
+			imports.add(importPath, subpackage)
+			body.WriteString("\n")
+		}
+	}
+
+	if strings.TrimSpace(body.String()) == "" {
+		logrus.Debugf("not writing an aggregator to %s because none of the interfaces of %s have non-generic types to re-export", g.cfg.OutDir, pkgName)
+		return nil
+	}
+
+	content, err := renderFile(g.packageName(), imports, body.String())
+	if err != nil {
+		return err
+	}
+	g.files[path.Join(g.cfg.OutDir, fileName)] = []byte(content)
+	return nil
+}
+
+// exportedTypeNames returns the names of the exported non-generic types declared in the generated file, in order.
+func exportedTypeNames(filePath string, src []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the generated %s: %v", filePath, err)
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if !typeSpec.Name.IsExported() {
+				continue
+			}
+			if typeSpec.TypeParams != nil {
+				logrus.Debugf("not re-exporting %s from the aggregator because it's generic", typeSpec.Name.Name)
+				continue
+			}
+			names = append(names, typeSpec.Name.Name)
+		}
+	}
+	return names, nil
+}
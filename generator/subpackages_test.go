@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSubpackages(t *testing.T) {
+	outDir, err := os.MkdirTemp("../testdata", "subpackages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(outDir) })
+
+	aggregator := generateFiles(t, Config{PkgPath: "../testdata/aggregate", OutDir: outDir, Subpackages: true})["aggregate_functypes.go"]
+
+	// Store's Get and Put are re-exported from getter and putter already.
+	importPath := "github.com/eaardal/functypes/testdata/" + filepath.Base(outDir)
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"` + importPath + `/getter"
+	"` + importPath + `/putter"
+	"` + importPath + `/store"
+)
+
+type Get = getter.Get
+
+type Put = putter.Put
+
+type Close = store.Close
+`
+	if aggregator != want {
+		t.Errorf("aggregator =\n%s\nwant\n%s", aggregator, want)
+	}
+
+	subpackages := map[string]string{
+		"getter": "package getter\n\ntype Get func(key string) (string, error)\n",
+		"putter": "package putter\n\ntype Put func(key string, value string) error\n",
+		"store":  "package store\n\ntype Close func() error\ntype Get func(key string) (string, error)\ntype Put func(key string, value string) error\n",
+	}
+	for name, body := range subpackages {
+		got := readFiles(t, filepath.Join(outDir, name))["aggregate_functypes.go"]
+		if want := generatedHeader + "\n\n" + body; got != want {
+			t.Errorf("%s/aggregate_functypes.go =\n%s\nwant\n%s", name, got, want)
+		}
+	}
+
+	goTestGenerated(t, Config{PkgPath: "../testdata/aggregate", Subpackages: true}, nil)
+}
+
+func TestSubpackageName(t *testing.T) {
+	tests := map[string]string{
+		"Reader":    "reader",
+		"HTTPStore": "httpstore",
+		"Type":      "typepkg",
+	}
+	for ifaceName, want := range tests {
+		if got := subpackageName(ifaceName); got != want {
+			t.Errorf("subpackageName(%q) = %q, want %q", ifaceName, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// summaryTitle is the heading of the --emit-summary file.
+const summaryTitle = "# Generated function types"
+
+// writeSummary writes the --emit-summary file: a Markdown table per package listing each interface, its methods and the function types generated for them, for reviewing the generated surface.
+// Only the interfaces rendered by this run are listed, so with --update it only covers the updated interfaces.
+func (g *generator) writeSummary(summaryPath string) error {
+	builder := &strings.Builder{}
+	builder.WriteString(summaryTitle + "\n")
+
+	// With --route a package's function types are spread over several files, which are grouped again here.
+	decls := append([]GeneratedDecl(nil), g.generated...)
+	sort.SliceStable(decls, func(i, j int) bool {
+		if decls[i].PkgPath != decls[j].PkgPath {
+			return decls[i].PkgPath < decls[j].PkgPath
+		}
+		return decls[i].Interface < decls[j].Interface
+	})
+
+	pkgPath := ""
+	for _, decl := range decls {
+		if decl.PkgPath != pkgPath {
+			pkgPath = decl.PkgPath
+			builder.WriteString(fmt.Sprintf("\n## %s\n\n", pkgPath))
+			builder.WriteString("| Interface | Method | Function type |\n")
+			builder.WriteString("| --- | --- | --- |\n")
+		}
+		builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n", markdownCode(decl.Interface), markdownCode(decl.Method), markdownCode(decl.Go)))
+	}
+
+	if err := os.WriteFile(summaryPath, []byte(builder.String()), filePerm); err != nil {
+		return fmt.Errorf("write %s with perm %d: %w", summaryPath, filePerm, err)
+	}
+	logrus.Infof("saved %s", summaryPath)
+	return nil
+}
+
+// markdownCode returns the text as a code span fit for a Markdown table cell. Pipes, like in the type set of an inline constraint, would end the cell, so they're escaped.
+func markdownCode(text string) string {
+	return "`" + strings.ReplaceAll(text, "|", `\|`) + "`"
+}
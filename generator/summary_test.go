@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSummary(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "summary.md")
+	generateFiles(t, Config{PkgPath: "../testdata", Include: "^(MyInterface|Reader)$", EmitSummary: summaryPath})
+
+	got, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := summaryTitle + "\n\n## github.com/eaardal/functypes/testdata\n\n" +
+		"| Interface | Method | Function type |\n" +
+		"| --- | --- | --- |\n" +
+		"| `MyInterface` | `Abc` | `type Abc func() (string, error)` |\n" +
+		"| `MyInterface` | `Bar` | `type Bar func(a string) error` |\n" +
+		"| `MyInterface` | `Foo` | `type Foo func(a string, b int, c ...string)` |\n" +
+		"| `Reader` | `Read` | `type Read func(p []byte) (n int, err error)` |\n"
+	if string(got) != want {
+		t.Errorf("summary =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateSummaryOfPrefixedFunctionTypes(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "summary.md")
+	generateFiles(t, Config{PkgPath: "../testdata/embedded", EmitSummary: summaryPath})
+
+	got, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Method column has the method name, also when the function type is prefixed with the interface name.
+	want := summaryTitle + "\n\n## github.com/eaardal/functypes/testdata/embedded\n\n" +
+		"| Interface | Method | Function type |\n" +
+		"| --- | --- | --- |\n" +
+		"| `Closer` | `Close` | `type CloserClose func() error` |\n" +
+		"| `ReadCloser` | `Close` | `type ReadCloserClose func() error` |\n" +
+		"| `ReadCloser` | `Name` | `type ReadCloserName func() string` |\n" +
+		"| `ReadCloser` | `Read` | `type ReadCloserRead func(p []byte) (n int, err error)` |\n"
+	if string(got) != want {
+		t.Errorf("summary =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	if got, want := markdownCode("type Sum[N int | float64] func(values ...N) N"), "`type Sum[N int \\| float64] func(values ...N) N`"; got != want {
+		t.Errorf("markdownCode() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateIntoSymlinkedOutDir(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("can't create symlinks: %v", err)
+	}
+
+	generateFiles(t, Config{PkgPath: "../testdata/routes", OutDir: link})
+
+	if _, err := os.Stat(filepath.Join(target, "routes_functypes.go")); err != nil {
+		t.Errorf("the generated file wasn't written through the symlink: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("the symlink was replaced")
+	}
+}
+
+func TestGenerateIntoBrokenSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "removed")
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("can't create symlinks: %v", err)
+	}
+
+	err := Generate(Config{PkgPath: "../testdata/routes", OutDir: link})
+	want := "the output directory " + link + " is a broken symlink to " + target + ": lstat " + target + ": no such file or directory"
+	if err == nil || err.Error() != want {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+}
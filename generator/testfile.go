@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// testFileContent returns the content of the <pkg>_functypes_test.go file written by --emit-test and --emit-examples.
+// For each adapter it contains a compile-time assertion that the adapter satisfies its source interface, plus a smoke test using the adapter through the interface. For each of the examples it contains an example function, see stringifyExample.
+func (g *generator) testFileContent(pkgName string, adapters []adapter, examples []string) (string, error) {
+	imports := newImportSet()
+	imports.local = g.localPath()
+	if len(adapters) > 0 {
+		imports.add("testing", "testing")
+	}
+	if len(examples) > 0 {
+		imports.add("fmt", "fmt")
+	}
+
+	body := &strings.Builder{}
+	for _, a := range adapters {
+		ifaceRef := imports.qualifiedName(a.iface.Pkg(), a.iface.Name())
+
+		// An adapter with value receivers implements the interface by itself, not only through a pointer.
+		value := fmt.Sprintf("&%s{}", a.structName)
+		if a.value {
+			value = a.structName + "{}"
+			body.WriteString(fmt.Sprintf("var _ %s = %s{}\n", ifaceRef, a.structName))
+		} else {
+			body.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n", ifaceRef, a.structName))
+		}
+		body.WriteString(fmt.Sprintf("\nfunc Test%s(t *testing.T) {\n", a.structName))
+		body.WriteString(fmt.Sprintf("\tvar impl %s = %s\n", ifaceRef, value))
+		body.WriteString("\tif impl == nil {\n")
+		body.WriteString(fmt.Sprintf("\t\tt.Fatal(\"expected %s to implement %s\")\n", a.structName, ifaceRef))
+		body.WriteString("\t}\n}\n\n")
+	}
+	for _, name := range examples {
+		body.WriteString(stringifyExample(name, g.cfg.EmitNilChecks) + "\n\n")
+	}
+	return renderFile(pkgName, imports, strings.TrimSuffix(body.String(), "\n"))
+}
+
+// stringifyExample returns a testable example for the function type with the given name, as a starting point for documenting how it's used.
+// What a meaningful implementation looks like is up to the consumer, so the example only declares a variable of the function type. Its output comment makes go test run it, which with --emit-nil-checks also checks that the IsSet method reports the nil variable as unset.
+func stringifyExample(name string, nilChecks bool) string {
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// Example%s shows how to declare a %s. Assign it a function literal with its signature, or a method value of an implementation.\n", name, name))
+	builder.WriteString(fmt.Sprintf("func Example%s() {\n", name))
+	builder.WriteString(fmt.Sprintf("\tvar f %s\n", name))
+	if nilChecks {
+		builder.WriteString("\tfmt.Println(f.IsSet())\n")
+		builder.WriteString("\t// Output: false\n}")
+	} else {
+		builder.WriteString("\tfmt.Println(f == nil)\n")
+		builder.WriteString("\t// Output: true\n}")
+	}
+	return builder.String()
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTestFile(t *testing.T) {
+	files := generateFiles(t, Config{PkgPath: "../testdata", EmitTest: true})
+
+	got, ok := files["testdata_functypes_test.go"]
+	if !ok {
+		t.Fatalf("Generate() wrote %v, want a testdata_functypes_test.go", files)
+	}
+	want := "var _ testdata.Reader = (*ReaderFuncs)(nil)\n\nfunc TestReaderFuncs(t *testing.T) {\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+}
+
+func TestGenerateTestFileCompilesAndPasses(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata", EmitTest: true}, nil)
+}
+
+func TestGenerateExamples(t *testing.T) {
+	files := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitExamples: true})
+
+	want := "// ExampleBar shows how to declare a Bar. Assign it a function literal with its signature, or a method value of an implementation.\nfunc ExampleBar() {\n\tvar f Bar\n\tfmt.Println(f == nil)\n\t// Output: true\n}\n"
+	if got := files["testdata_functypes_test.go"]; !strings.Contains(got, want) {
+		t.Errorf("testdata_functypes_test.go =\n%s\nwant it to contain\n%s", got, want)
+	}
+}
+
+func TestGenerateExamplesCompileAndRun(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitExamples: true}, nil)
+}
+
+func TestGenerateTestFileOfUnexportedInterfacesCompiles(t *testing.T) {
+	// localctx's context interface is unexported, so the test file generated outside localctx can't assert its adapter implements it.
+	files := generateFiles(t, Config{PkgPath: "../testdata/localctx", EmitTest: true})
+	if got := files["localctx_functypes_test.go"]; strings.Contains(got, "localctx.context") {
+		t.Errorf("localctx_functypes_test.go =\n%s\nwant it not to reference localctx.context", got)
+	}
+
+	goTestGenerated(t, Config{PkgPath: "../testdata/localctx", EmitTest: true}, nil)
+}
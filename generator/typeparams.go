@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"go/types"
+	"strings"
+)
+
+// typeParamLists are the type parameters of a function type generated from a method of a generic interface, in the two forms needed by the generated code.
+// Both are empty for methods of non-generic interfaces.
+type typeParamLists struct {
+	// decl is the type parameter list declaring the parameters with their constraints, such as "[K comparable, V any]".
+	decl string
+	// use is the type argument list referring to the declared parameters, such as "[K, V]".
+	use string
+}
+
+// methodTypeParams returns the type parameters of the generic interface (tparams) needed by the function type of the method.
+// Only the type parameters used by the method's signature are kept, plus the ones the constraints of those refer to, so that a method using a single type parameter gets a function type with a single type parameter. The order of the interface's type parameter list is preserved.
+// Constraints are rendered like any other type, so constraints from other packages (like cmp.Ordered) are qualified and imported.
+func (r *renderer) methodTypeParams(sig *types.Signature, tparams *types.TypeParamList) typeParamLists {
+	if tparams.Len() == 0 {
+		return typeParamLists{}
+	}
+
+	used := make(map[*types.TypeParam]bool)
+	collectTypeParams(sig, used)
+
+	// The constraints of the used type parameters may refer to other type parameters, like V in [K comparable, V Keyed[K]], which must be declared as well.
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < tparams.Len(); i++ {
+			tp := tparams.At(i)
+			if !used[tp] {
+				continue
+			}
+			before := len(used)
+			collectTypeParams(tp.Constraint(), used)
+			changed = changed || len(used) != before
+		}
+	}
+
+	var decls, uses []string
+	for i := 0; i < tparams.Len(); i++ {
+		tp := tparams.At(i)
+		if !used[tp] {
+			continue
+		}
+		decls = append(decls, tp.Obj().Name()+" "+r.typeString(tp.Constraint()))
+		uses = append(uses, tp.Obj().Name())
+	}
+
+	if len(decls) == 0 {
+		return typeParamLists{}
+	}
+	return typeParamLists{
+		decl: "[" + strings.Join(decls, ", ") + "]",
+		use:  "[" + strings.Join(uses, ", ") + "]",
+	}
+}
+
+// collectTypeParams adds the type parameters the type is composed of to found.
+func collectTypeParams(t types.Type, found map[*types.TypeParam]bool) {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		found[t] = true
+	case *types.Pointer:
+		collectTypeParams(t.Elem(), found)
+	case *types.Slice:
+		collectTypeParams(t.Elem(), found)
+	case *types.Array:
+		collectTypeParams(t.Elem(), found)
+	case *types.Chan:
+		collectTypeParams(t.Elem(), found)
+	case *types.Map:
+		collectTypeParams(t.Key(), found)
+		collectTypeParams(t.Elem(), found)
+	case *types.Signature:
+		collectTupleTypeParams(t.Params(), found)
+		collectTupleTypeParams(t.Results(), found)
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			collectTypeParams(t.Field(i).Type(), found)
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			collectTypeParams(t.ExplicitMethod(i).Type(), found)
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			collectTypeParams(t.EmbeddedType(i), found)
+		}
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			collectTypeParams(t.Term(i).Type(), found)
+		}
+	case *types.Named:
+		typeArgs := t.TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			collectTypeParams(typeArgs.At(i), found)
+		}
+	}
+}
+
+// collectTupleTypeParams adds the type parameters used by the variables in the tuple to found.
+func collectTupleTypeParams(tuple *types.Tuple, found map[*types.TypeParam]bool) {
+	for i := 0; i < tuple.Len(); i++ {
+		collectTypeParams(tuple.At(i).Type(), found)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGenerateTypeParams(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/ordered"})["ordered_functypes.go"]
+
+	// Each function type declares only the type parameters it uses, with the constraints of other packages qualified and imported.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"cmp"
+	"github.com/eaardal/functypes/testdata/constraints"
+)
+
+type Lookup[K comparable] func(key K) (int, error)
+type Values[K comparable, V ~[]K] func() V
+type Index[K constraints.Stringer] func(keys []K) map[K]int
+type Sort[T cmp.Ordered] func(items []T) []T
+type Sum[N constraints.Number] func(values ...N) N
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The wrappers declare the same type parameters as their function types.
+	goTestGenerated(t, Config{PkgPath: "../testdata/ordered", EmitMust: true, EmitStubs: true}, nil)
+}
+
+func TestGenerateComparableConstraints(t *testing.T) {
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: "../testdata/sets", EmitAdapter: true})["sets_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type Key func() string
+type Add[T comparable] func(item T)
+type Has[T comparable] func(item T) bool
+type Items[T comparable] func() []T
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	wantWarning := "skipping adapter for Keyed because it can only be used as a type constraint"
+	if !slices.Contains(logged(), wantWarning) {
+		t.Errorf("warnings = %q, want %q", logged(), wantWarning)
+	}
+}
@@ -0,0 +1,125 @@
+package generator
+
+import "go/types"
+
+// hasUnexportedMember returns true if the type is, or is composed of, an anonymous struct with unexported fields or an anonymous interface with unexported methods.
+// Unexported field names belong to the package declaring the struct, so a struct like struct{ x int } written in the generated package is a different type than the one in the source package, and the generated code couldn't be used with the source interface.
+// Named types are not looked into, since they're referenced by name rather than rewritten.
+func hasUnexportedMember(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return hasUnexportedMember(t.Elem())
+	case *types.Slice:
+		return hasUnexportedMember(t.Elem())
+	case *types.Array:
+		return hasUnexportedMember(t.Elem())
+	case *types.Chan:
+		return hasUnexportedMember(t.Elem())
+	case *types.Map:
+		return hasUnexportedMember(t.Key()) || hasUnexportedMember(t.Elem())
+	case *types.Signature:
+		return hasUnexportedTupleMember(t.Params()) || hasUnexportedTupleMember(t.Results())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if !t.Field(i).Exported() || hasUnexportedMember(t.Field(i).Type()) {
+				return true
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			if !t.Method(i).Exported() || hasUnexportedMember(t.Method(i).Type()) {
+				return true
+			}
+		}
+	case *types.Named:
+		typeArgs := t.TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			if hasUnexportedMember(typeArgs.At(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasUnexportedTupleMember returns true if any of the variables in the tuple has a type with unexported members, see hasUnexportedMember.
+func hasUnexportedTupleMember(tuple *types.Tuple) bool {
+	for i := 0; i < tuple.Len(); i++ {
+		if hasUnexportedMember(tuple.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// unexportedTypeName returns the name of an unexported named type referenced by the type, like the opts of Do(o opts) error, as <package>.<name>. It returns an empty string if there's none.
+// Unexported names can only be referenced from their own package, so they're fine in files generated into it with --qualify-relative-to, and nowhere else. The predeclared error has no package and is never reported.
+func (r *renderer) unexportedTypeName(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return r.unexportedTypeName(t.Elem())
+	case *types.Slice:
+		return r.unexportedTypeName(t.Elem())
+	case *types.Array:
+		return r.unexportedTypeName(t.Elem())
+	case *types.Chan:
+		return r.unexportedTypeName(t.Elem())
+	case *types.Map:
+		if name := r.unexportedTypeName(t.Key()); name != "" {
+			return name
+		}
+		return r.unexportedTypeName(t.Elem())
+	case *types.Signature:
+		if name := r.unexportedTypeName(t.Params()); name != "" {
+			return name
+		}
+		return r.unexportedTypeName(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if name := r.unexportedTypeName(t.At(i).Type()); name != "" {
+				return name
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if name := r.unexportedTypeName(t.Field(i).Type()); name != "" {
+				return name
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			if name := r.unexportedTypeName(t.Method(i).Type()); name != "" {
+				return name
+			}
+		}
+	case *types.Alias:
+		if name := r.unexportedObjName(t.Obj()); name != "" {
+			return name
+		}
+		typeArgs := t.TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			if name := r.unexportedTypeName(typeArgs.At(i)); name != "" {
+				return name
+			}
+		}
+	case *types.Named:
+		if name := r.unexportedObjName(t.Obj()); name != "" {
+			return name
+		}
+		typeArgs := t.TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			if name := r.unexportedTypeName(typeArgs.At(i)); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// unexportedObjName returns the object's name as <package>.<name> if it's unexported and declared outside the --qualify-relative-to package, or an empty string otherwise.
+func (r *renderer) unexportedObjName(obj *types.TypeName) string {
+	if obj.Exported() || obj.Pkg() == nil || obj.Pkg().Path() == r.localPath() {
+		return ""
+	}
+	return obj.Pkg().Name() + "." + obj.Name()
+}
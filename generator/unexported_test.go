@@ -0,0 +1,34 @@
+package generator
+
+import "testing"
+
+func TestGenerateCompositeResults(t *testing.T) {
+	cfg := Config{PkgPath: "../testdata/shapes"}
+	got := generateFiles(t, cfg)["shapes_functypes.go"]
+
+	// Every geo.Point nested in the composite results is qualified, and Tracer's Trace is skipped.
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata/geo"
+)
+
+type Bounds func() struct {
+	Min geo.Point
+	Max geo.Point
+}
+type Endpoints func() [2]geo.Point
+type Path func() map[string][]*[2]geo.Point
+type Stream func() <-chan struct {
+	From geo.Point
+	To   geo.Point
+}
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+
+	goTestGenerated(t, cfg, nil)
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// declSegment is the source of a top-level declaration in a generated file, including its doc comment.
+type declSegment struct {
+	// key identifies the declaration across generations, see declName.
+	key string
+	// text is the declaration's source exactly as it appears in the file.
+	text string
+	// separator is the source between the previous declaration and this one, which is a newline or a blank line in generated files. It's empty for the first declaration.
+	separator string
+}
+
+// importSpec is an import of a generated file. name is empty if the package is imported without an alias.
+type importSpec struct {
+	path, name string
+}
+
+// parseGeneratedFile returns the imports and the top-level declarations of a generated file, in the order they appear.
+func parseGeneratedFile(fileName string, src []byte) ([]importSpec, []declSegment, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %v", fileName, err)
+	}
+
+	var imports []importSpec
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		imp := importSpec{path: importPath}
+		if spec.Name != nil {
+			imp.name = spec.Name.Name
+		}
+		imports = append(imports, imp)
+	}
+
+	var segments []declSegment
+	previousEnd := -1
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+
+		start := decl.Pos()
+		if doc := declDoc(decl); doc != nil {
+			start = doc.Pos()
+		}
+		startOffset, endOffset := fset.Position(start).Offset, fset.Position(decl.End()).Offset
+
+		segment := declSegment{key: declName(decl), text: string(src[startOffset:endOffset])}
+		if previousEnd >= 0 {
+			segment.separator = string(src[previousEnd:startOffset])
+		}
+		segments = append(segments, segment)
+		previousEnd = endOffset
+	}
+	return imports, segments, nil
+}
+
+// declDoc returns the doc comment of the declaration, or nil if it has none.
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return decl.Doc
+	case *ast.GenDecl:
+		return decl.Doc
+	}
+	return nil
+}
+
+// readUpdatedFile implements --update for a single output file: the declarations rendered for the updated interfaces (newSrc) are spliced into the file previously generated at the path.
+// Returns newSrc as is if there's no previous file yet.
+func readUpdatedFile(outFilePath, pkgName string, newSrc []byte) ([]byte, error) {
+	oldSrc, err := os.ReadFile(outFilePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newSrc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", outFilePath, err)
+	}
+	return spliceUpdate(outFilePath, pkgName, oldSrc, newSrc)
+}
+
+// spliceUpdate returns the old generated file with the declarations of the new one spliced in.
+// A declaration in both files is replaced in place, declarations only in the new file are appended, and all other declarations are kept byte for byte. The imports are merged, and imports no longer used by any declaration are dropped.
+// Declarations which were removed from an updated interface can't be told apart from the declarations of other interfaces, so they're kept until the file is fully regenerated.
+func spliceUpdate(fileName, pkgName string, oldSrc, newSrc []byte) ([]byte, error) {
+	oldImports, oldDecls, err := parseGeneratedFile(fileName, oldSrc)
+	if err != nil {
+		return nil, err
+	}
+	newImports, newDecls, err := parseGeneratedFile(fileName, newSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err := mergeImports(oldImports, newImports)
+	if err != nil {
+		return nil, fmt.Errorf("can't update %s, regenerate it fully instead: %v", fileName, err)
+	}
+
+	replacements := make(map[string]string)
+	for _, decl := range newDecls {
+		replacements[decl.key] = decl.text
+	}
+
+	body := &strings.Builder{}
+	spliced := make(map[string]bool)
+	for _, decl := range oldDecls {
+		body.WriteString(decl.separator)
+		if text, ok := replacements[decl.key]; ok {
+			body.WriteString(text)
+			spliced[decl.key] = true
+			continue
+		}
+		body.WriteString(decl.text)
+	}
+	for _, decl := range newDecls {
+		if !spliced[decl.key] {
+			body.WriteString("\n\n" + decl.text)
+		}
+	}
+	bodySrc := body.String()
+
+	used, err := usedImports(fileName, imports, bodySrc)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderFile(pkgName, used, bodySrc)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// mergeImports returns the union of the imports of the old and new generation of a file. It fails if the generations import a package under different names, or different packages under the same name, since the declarations of both generations couldn't refer to them.
+func mergeImports(oldImports, newImports []importSpec) ([]importSpec, error) {
+	byPath := make(map[string]importSpec)
+	byName := make(map[string]string)
+
+	var merged []importSpec
+	for _, imp := range append(oldImports, newImports...) {
+		name := imp.name
+		if name == "" {
+			name = path.Base(imp.path)
+		}
+
+		if existing, ok := byPath[imp.path]; ok {
+			if existing.name != imp.name {
+				return nil, fmt.Errorf("%s is imported both as %q and %q", imp.path, existing.name, imp.name)
+			}
+			continue
+		}
+		if other, ok := byName[name]; ok {
+			return nil, fmt.Errorf("both %s and %s are imported as %s", other, imp.path, name)
+		}
+
+		byPath[imp.path] = imp
+		byName[name] = imp.path
+		merged = append(merged, imp)
+	}
+	return merged, nil
+}
+
+// usedImports returns an importSet with the imports referenced by the declarations.
+func usedImports(fileName string, imports []importSpec, bodySrc string) (*importSet, error) {
+	used := newImportSet()
+	if len(imports) == 0 {
+		return used, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, packageLine(defaultPackageName)+"\n\n"+importDecl(imports)+"\n\n"+bodySrc, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the updated %s: %v", fileName, err)
+	}
+
+	for _, imp := range imports {
+		if !astutil.UsesImport(file, imp.path) {
+			continue
+		}
+		if imp.name == "" {
+			used.add(imp.path, path.Base(imp.path))
+		} else {
+			used.imports[imp.path] = &importEntry{path: imp.path, name: imp.name}
+			used.taken[imp.name] = true
+		}
+	}
+	return used, nil
+}
+
+// importDecl returns an import declaration for the imports.
+func importDecl(imports []importSpec) string {
+	builder := &strings.Builder{}
+	builder.WriteString("import (\n")
+	for _, imp := range imports {
+		builder.WriteString(fmt.Sprintf("\t%s %q\n", imp.name, imp.path))
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
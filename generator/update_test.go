@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateUpdate(t *testing.T) {
+	outDir := t.TempDir()
+	outFile := filepath.Join(outDir, "testdata_functypes.go")
+	full := generateFiles(t, Config{PkgPath: "../testdata", OutDir: outDir})["testdata_functypes.go"]
+
+	// Stale declarations of MyInterface, and a hand edit of AnyStore's Get which --update must leave alone.
+	old := strings.NewReplacer(
+		"type Bar func(a string) error", "type Bar func(a int) error",
+		"type Foo func(a string, b int, c ...string)", "type Foo func()",
+		"type Get func(key string) any", "type Get func(key string) (any, bool)",
+	).Replace(full)
+	if err := os.WriteFile(outFile, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generateFiles(t, Config{PkgPath: "../testdata", OutDir: outDir, Update: "MyInterface"})["testdata_functypes.go"]
+
+	want := strings.Replace(full, "type Get func(key string) any", "type Get func(key string) (any, bool)", 1)
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateUpdateWithoutPreviousFile(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Update: "MyInterface"})["testdata_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type Abc func() (string, error)
+type Bar func(a string) error
+type Foo func(a string, b int, c ...string)
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUpdateCantBeCombinedWithCompatWith(t *testing.T) {
+	_, err := newGenerator(Config{Update: "MyInterface", CompatWith: "old.go"})
+	if want := "--update and --compat-with can't be used together"; err == nil || err.Error() != want {
+		t.Errorf("newGenerator() error = %v, want %q", err, want)
+	}
+}
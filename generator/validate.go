@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// validate implements --validate: it type checks the rendered files of each output directory as the package they make up, and returns the first type error with the offending line.
+// This catches bugs like unqualified types or missing imports before anything is written. Imports are resolved to the loaded packages where possible, so the generated code is checked against the very types it was generated from.
+func (g *generator) validate(pkgs []*packages.Package) error {
+	byDir := make(map[string][]string)
+	for outFilePath := range g.files {
+		// Only Go code can be type checked, not the files of a custom --emitter.
+		if filepath.Ext(outFilePath) != ".go" {
+			continue
+		}
+		dir := filepath.Dir(outFilePath)
+		byDir[dir] = append(byDir[dir], outFilePath)
+	}
+
+	// Deeper directories go first, so the aggregator of --subpackages is checked against the subpackages it imports.
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if di, dj := strings.Count(dirs[i], "/"), strings.Count(dirs[j], "/"); di != dj {
+			return di > dj
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	generated := make(map[string]*types.Package)
+	for _, dir := range dirs {
+		paths := byDir[dir]
+		sort.Strings(paths)
+
+		pkg, err := g.validateDir(pkgs, dir, paths, generated)
+		if err != nil {
+			return err
+		}
+		generated[pkg.Path()] = pkg
+	}
+	return nil
+}
+
+// validateDir type checks the rendered files of the output directory as one package, and returns it. Imports of the packages generated before are resolved to those.
+func (g *generator) validateDir(pkgs []*packages.Package, dir string, paths []string, generated map[string]*types.Package) (*types.Package, error) {
+	fset := token.NewFileSet()
+
+	var files []*ast.File
+	for _, outFilePath := range paths {
+		file, err := parser.ParseFile(fset, outFilePath, g.files[outFilePath], 0)
+		if err != nil {
+			return nil, fmt.Errorf("--validate: failed to parse the generated %s: %v", outFilePath, err)
+		}
+		if local := g.localPath(); local != "" {
+			addDotImport(file, local)
+		}
+		files = append(files, file)
+	}
+
+	// The dot import standing in for the rest of the local package is unused in files not referencing it.
+	unusedLocal := strconv.Quote(g.localPath()) + " imported and not used"
+
+	imp := &loadedImporter{pkgs: pkgs, generated: generated, fallback: importer.ForCompiler(fset, "source", nil)}
+	if g.localPkg != nil {
+		imp.local = g.standInPackage(pkgs, paths)
+	}
+
+	var firstErr error
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			if firstErr == nil && !(g.localPath() != "" && strings.Contains(err.Error(), unusedLocal)) {
+				firstErr = err
+			}
+		},
+	}
+	importPath, ok := outDirImportPath(pkgs, dir)
+	if !ok {
+		importPath = g.packageNameFor(dir)
+	}
+	pkg, _ := conf.Check(importPath, fset, files, nil)
+
+	if firstErr == nil {
+		return pkg, nil
+	}
+	typeErr, ok := firstErr.(types.Error)
+	if !ok {
+		return nil, fmt.Errorf("--validate: the generated code doesn't type check: %v", firstErr)
+	}
+
+	position := fset.Position(typeErr.Pos)
+	return nil, fmt.Errorf("--validate: the generated code doesn't type check: %v\n\t%s", typeErr, sourceLine(g.files[position.Filename], position.Line))
+}
+
+// addDotImport adds a dot import of the package to the file.
+// With --qualify-relative-to the generated files are part of another package and reference its types without qualifier, so they're checked with the package dot imported, standing in for its other files. Declarations colliding with the package's own are caught this way too.
+func addDotImport(file *ast.File, importPath string) {
+	spec := &ast.ImportSpec{Name: ast.NewIdent("."), Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(importPath)}}
+	file.Imports = append(file.Imports, spec)
+	file.Decls = append([]ast.Decl{&ast.GenDecl{Tok: token.IMPORT, Specs: []ast.Spec{spec}}}, file.Decls...)
+}
+
+// standInPackage returns a copy of the --qualify-relative-to package without the declarations of the given generated files, to check the new generation of those files against.
+// When the output directory is the package's directory, the package was loaded with the previous generation of the files, whose declarations would otherwise collide with the new ones.
+func (g *generator) standInPackage(pkgs []*packages.Package, generatedPaths []string) *types.Package {
+	generated := make(map[string]bool)
+	for _, generatedPath := range generatedPaths {
+		if abs, err := filepath.Abs(generatedPath); err == nil {
+			generated[abs] = true
+		}
+	}
+
+	var fset *token.FileSet
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == g.localPkg.Path() {
+			fset = pkg.Fset
+		}
+	}
+
+	standIn := types.NewPackage(g.localPkg.Path(), g.localPkg.Name())
+	for _, name := range g.localPkg.Scope().Names() {
+		obj := g.localPkg.Scope().Lookup(name)
+		if fset != nil && generated[fset.Position(obj.Pos()).Filename] {
+			continue
+		}
+		standIn.Scope().Insert(obj)
+	}
+	standIn.MarkComplete()
+	return standIn
+}
+
+// sourceLine returns the given line (counting from 1) of the source, or an empty string if it has no such line.
+func sourceLine(src []byte, line int) string {
+	lines := strings.Split(string(src), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// loadedImporter imports packages from the loaded packages and their imports, and falls back to another importer for packages they don't reference, like the testing package imported by the --emit-test file.
+type loadedImporter struct {
+	pkgs []*packages.Package
+	// generated are the packages generated into other output directories, by import path.
+	generated map[string]*types.Package
+	fallback  types.Importer
+	// local is imported in place of the --qualify-relative-to package, if set (see standInPackage).
+	local *types.Package
+}
+
+// Import implements types.Importer.
+func (i *loadedImporter) Import(path string) (*types.Package, error) {
+	if i.local != nil && path == i.local.Path() {
+		return i.local, nil
+	}
+	if pkg, ok := i.generated[path]; ok {
+		return pkg, nil
+	}
+	pkg := findTypesPackage(i.pkgs, path)
+	if pkg == nil || pkg.Path() != path {
+		return i.fallback.Import(path)
+	}
+	if !pkg.Complete() {
+		if err := i.complete(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return pkg, nil
+}
+
+// complete adds the declarations missing from the incomplete loaded package from the fallback's import of it, and marks it complete.
+// Indirect dependencies are only loaded as far as the packages importing them refer to them, while the generated code can use more of them, like the fmt.Println of the --emit-examples file. The loaded declarations are kept rather than importing the whole package from the fallback, since the converted methods refer to those: an io.Writer of the fallback isn't identical to the one of an embedded interface's method.
+func (i *loadedImporter) complete(pkg *types.Package) error {
+	full, err := i.fallback.Import(pkg.Path())
+	if err != nil {
+		return err
+	}
+	for _, name := range full.Scope().Names() {
+		if pkg.Scope().Lookup(name) == nil {
+			pkg.Scope().Insert(full.Scope().Lookup(name))
+		}
+	}
+	pkg.MarkComplete()
+	return nil
+}
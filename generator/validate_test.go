@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	const header = "// Code generated by functypes. DO NOT EDIT.\n\npackage functypes\n\n"
+
+	tests := []struct {
+		name string
+		// files are the rendered files, by name in the output directory.
+		files map[string]string
+		// wantErr are the parts of the expected error, none if the files are valid.
+		wantErr []string
+	}{
+		{
+			name:  "valid",
+			files: map[string]string{"a_functypes.go": header + "import \"io\"\n\ntype Read func(r io.Reader) error\n"},
+		},
+		{
+			name:    "missing import",
+			files:   map[string]string{"a_functypes.go": header + "type Read func(r io.Reader) error\n"},
+			wantErr: []string{"doesn't type check", "undefined: io", "\ttype Read func(r io.Reader) error"},
+		},
+		{
+			name:    "unqualified type",
+			files:   map[string]string{"a_functypes.go": header + "type Get func() Item\n"},
+			wantErr: []string{"doesn't type check", "undefined: Item", "\ttype Get func() Item"},
+		},
+		{
+			name: "declared in two files of the output directory",
+			files: map[string]string{
+				"a_functypes.go": header + "type Close func() error\n",
+				"b_functypes.go": header + "type Close func() error\n",
+			},
+			wantErr: []string{"doesn't type check", "Close redeclared"},
+		},
+		{
+			name:    "unparsable",
+			files:   map[string]string{"a_functypes.go": header + "type Close func(\n"},
+			wantErr: []string{"failed to parse the generated"},
+		},
+		{
+			name:  "files of custom emitters aren't checked",
+			files: map[string]string{"a_functypes.txt": "Close: func() error\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := t.TempDir()
+			g, err := newGenerator(Config{PkgPath: "../testdata/embedded", OutDir: outDir, Validate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				g.files[filepath.Join(outDir, name)] = []byte(content)
+			}
+
+			err = g.validate(nil)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validate() error = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validate() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderValidates(t *testing.T) {
+	// widen's interfaces have parameters of struct types which --widen-params replaces with interfaces, for which --emit-stubs, --emit-vtable and --emit-adapter have to render matching code.
+	_, err := Render(Config{PkgPath: "../testdata/widen", OutDir: t.TempDir(), WidenParams: true, EmitStubs: true, EmitVTable: true, EmitAdapter: true, Validate: true})
+	if err != nil {
+		t.Errorf("Render() error = %v", err)
+	}
+}
+
+func TestRenderValidatesAgainstIndirectDependencies(t *testing.T) {
+	// The indirect dependencies are only loaded as far as the packages importing them refer to them. The --emit-examples file of pointers uses fmt.Println, which pointers' dependencies don't, and the adapter of Proxy implements methods referring to the net/http types pointers refers to.
+	proxy := writeTestPackage(t, map[string]string{
+		"proxy.go": "package proxy\n\nimport \"github.com/eaardal/functypes/testdata/pointers\"\n\ntype Proxy interface {\n\tpointers.Resolver\n}\n",
+	})
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "examples", cfg: Config{PkgPath: "../testdata/pointers", EmitExamples: true}},
+		{name: "adapter of embedded interface", cfg: Config{PkgPath: "./" + proxy, EmitAdapter: true, EmitTest: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.OutDir = t.TempDir()
+			tt.cfg.Validate = true
+			if _, err := Render(tt.cfg); err != nil {
+				t.Errorf("Render() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// vtableStructName returns the name of the vtable struct generated for the named interface.
+func vtableStructName(ifaceName string) string {
+	return ifaceName + "VTable"
+}
+
+// stringifyVTable will take an interface and emit a vtable struct with one function type field per method, named after the method (capitalized for the unexported methods converted with --export-unexported), plus a Populate method filling the fields with the method values of an implementation of the interface.
+// It's the inverse of the adapter: where the adapter implements the interface from functions, the vtable breaks an implementation down into functions, for passing it across a plugin boundary as a table of plain function values.
+func (r *renderer) stringifyVTable(named *types.Named) string {
+	ifaceName := named.Obj().Name()
+	iface := named.Underlying().(*types.Interface)
+	structName := vtableStructName(ifaceName)
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// %s holds the methods of a %s as function values.\n", structName, ifaceName))
+	builder.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+	for i := 0; i < iface.NumMethods(); i++ {
+		meth := iface.Method(i)
+		builder.WriteString(fmt.Sprintf("\t%s %s\n", capitalize(meth.Name()), r.funcTypeName(named.Obj(), meth.Name())))
+	}
+	builder.WriteString("}\n\n")
+
+	builder.WriteString("// Populate fills the vtable with the methods of impl.\n")
+	builder.WriteString(fmt.Sprintf("func (vt *%s) Populate(impl %s) {\n", structName, r.typeString(named)))
+	for i := 0; i < iface.NumMethods(); i++ {
+		meth := iface.Method(i)
+		builder.WriteString(fmt.Sprintf("\tvt.%s = impl.%s\n", capitalize(meth.Name()), meth.Name()))
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
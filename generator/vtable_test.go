@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateVTable(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitVTable: true})["testdata_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+import (
+	"github.com/eaardal/functypes/testdata"
+)
+
+type Abc func() (string, error)
+type Bar func(a string) error
+type Foo func(a string, b int, c ...string)
+
+// MyInterfaceVTable holds the methods of a MyInterface as function values.
+type MyInterfaceVTable struct {
+	Abc Abc
+	Bar Bar
+	Foo Foo
+}
+
+// Populate fills the vtable with the methods of impl.
+func (vt *MyInterfaceVTable) Populate(impl testdata.MyInterface) {
+	vt.Abc = impl.Abc
+	vt.Bar = impl.Bar
+	vt.Foo = impl.Foo
+}
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateVTablePopulate(t *testing.T) {
+	// Populate is called with a hand-written implementation, whose methods the vtable's fields call then.
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitVTable: true}, map[string]string{
+		"populate_test.go": `package functypes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eaardal/functypes/testdata"
+)
+
+type impl struct {
+	foo []string
+}
+
+func (i *impl) Abc() (string, error) { return "abc", nil }
+func (i *impl) Bar(a string) error { return errors.New(a) }
+func (i *impl) Foo(a string, b int, c ...string) {
+	i.foo = append([]string{a}, c...)
+}
+
+var _ testdata.MyInterface = (*impl)(nil)
+
+func TestPopulate(t *testing.T) {
+	i := &impl{}
+	var vt MyInterfaceVTable
+	vt.Populate(i)
+
+	if s, err := vt.Abc(); s != "abc" || err != nil {
+		t.Errorf("Abc() = %q, %v, want abc, nil", s, err)
+	}
+	if err := vt.Bar("failed"); err == nil || err.Error() != "failed" {
+		t.Errorf("Bar() error = %v, want failed", err)
+	}
+	vt.Foo("a", 1, "b")
+	if len(i.foo) != 2 || i.foo[0] != "a" || i.foo[1] != "b" {
+		t.Errorf("Foo() got %q, want [a b]", i.foo)
+	}
+}
+`,
+	})
+}
+
+func TestGenerateSkipsVTablesOfUnexportedInterfaces(t *testing.T) {
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: "../testdata/localctx", EmitVTable: true, EmitAdapter: true})["localctx_functypes.go"]
+
+	if strings.Contains(got, "contextVTable") || strings.Contains(got, "contextFuncs") {
+		t.Errorf("Generate() =\n%s\nwant no vtable or adapter for context", got)
+	}
+	if !strings.Contains(got, "func (vt *HandlerVTable) Populate(impl localctx.Handler) {") {
+		t.Errorf("Generate() =\n%s\nwant the vtable of Handler", got)
+	}
+
+	wantWarnings := []string{
+		"skipping vtable for context because it's unexported, so it can only be referenced inside github.com/eaardal/functypes/testdata/localctx",
+		"skipping adapter for context because it's unexported, so it can only be referenced inside github.com/eaardal/functypes/testdata/localctx",
+	}
+	if got := logged(); !reflect.DeepEqual(got, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", got, wantWarnings)
+	}
+
+	// Inside the package, the vtable can reference the interface.
+	local := generateFiles(t, Config{PkgPath: "../testdata/localctx", QualifyRelativeTo: "github.com/eaardal/functypes/testdata/localctx", EmitVTable: true})["localctx_functypes.go"]
+	if !strings.Contains(local, "func (vt *contextVTable) Populate(impl context) {") {
+		t.Errorf("Generate() =\n%s\nwant the vtable of context", local)
+	}
+}
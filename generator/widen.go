@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"go/types"
+	"sort"
+)
+
+// localInterfaces returns the non-empty interfaces declared in the package, used as candidates by --widen-params. The interfaces are sorted by name so the choice between equally narrow interfaces is deterministic.
+func localInterfaces(pkg *types.Package) []*types.Named {
+	var ifaces []*types.Named
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		named, ok := scope.Lookup(name).Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		if iface, ok := named.Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+			ifaces = append(ifaces, named)
+		}
+	}
+	sort.Slice(ifaces, func(i, j int) bool {
+		return ifaces[i].Obj().Name() < ifaces[j].Obj().Name()
+	})
+	return ifaces
+}
+
+// widenSignature implements --widen-params: every parameter typed as a concrete struct (T or *T where T is a named struct type) is replaced by the narrowest interface declared in the scanned package that the parameter's type implements.
+// The narrowest interface is the one with the fewest methods, and ties are broken by interface name. Parameters without a matching interface keep their type, so the signature is unchanged if nothing can be widened.
+// Only the function type is widened: a function accepting the interface still accepts the struct, so adapters and wrappers forwarding the original struct keep compiling.
+func (r *renderer) widenSignature(sig *types.Signature) *types.Signature {
+	if len(r.widenCandidates) == 0 {
+		return sig
+	}
+
+	params := sig.Params()
+	widened := make([]*types.Var, params.Len())
+	changed := false
+
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+		widened[i] = param
+
+		if sig.Variadic() && i == params.Len()-1 {
+			continue
+		}
+		if !isConcreteStruct(param.Type()) {
+			continue
+		}
+
+		if iface := r.narrowestInterface(param.Type()); iface != nil {
+			r.log.Debugf("widening parameter %s %s to %s", param.Name(), types.TypeString(param.Type(), nil), iface.Obj().Name())
+			widened[i] = types.NewParam(param.Pos(), param.Pkg(), param.Name(), iface)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return sig
+	}
+	r.widenedParams = true
+	return types.NewSignatureType(nil, nil, nil, types.NewTuple(widened...), sig.Results(), sig.Variadic())
+}
+
+// narrowestInterface returns the candidate interface with the fewest methods that t implements, or nil if there is none.
+func (r *renderer) narrowestInterface(t types.Type) *types.Named {
+	var narrowest *types.Named
+	for _, candidate := range r.widenCandidates {
+		iface := candidate.Underlying().(*types.Interface)
+		if !types.Implements(t, iface) {
+			continue
+		}
+		if narrowest == nil || iface.NumMethods() < narrowest.Underlying().(*types.Interface).NumMethods() {
+			narrowest = candidate
+		}
+	}
+	return narrowest
+}
+
+// isConcreteStruct returns true if t is a named struct type or a pointer to one.
+func isConcreteStruct(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	_, ok = named.Underlying().(*types.Struct)
+	return ok
+}
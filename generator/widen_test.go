@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateWidenParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		widenParams bool
+		want        string
+	}{
+		{
+			name: "struct parameters are kept by default",
+			want: "type Upload func(f *widen.File, opts widen.Options) error\n",
+		},
+		{
+			name:        "struct parameters are widened to the narrowest local interface",
+			widenParams: true,
+			// Options implements no local interface and keeps its type.
+			want: "type Upload func(f widen.Named, opts widen.Options) error\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateFiles(t, Config{PkgPath: "../testdata/widen", WidenParams: tt.widenParams})["widen_functypes.go"]
+
+			want := generatedHeader + "\n\npackage functypes\n\nimport (\n\t\"github.com/eaardal/functypes/testdata/widen\"\n)\n\ntype Name func() string\n" + tt.want
+			if got != want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateStubsOfWidenedParams(t *testing.T) {
+	// --validate type checks that the stubs return the function types they stub.
+	got := generateFiles(t, Config{PkgPath: "../testdata/widen", WidenParams: true, EmitStubs: true, Validate: true})["widen_functypes.go"]
+
+	want := "func StubUpload() Upload {\n\treturn func(f widen.Named, opts widen.Options) error {"
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+}
+
+func TestGenerateSkipsVTablesOfWidenedParams(t *testing.T) {
+	logged := warnings(t)
+	got := generateFiles(t, Config{PkgPath: "../testdata/widen", WidenParams: true, EmitVTable: true, EmitAdapter: true})["widen_functypes.go"]
+
+	if strings.Contains(got, "UploaderVTable") {
+		t.Errorf("Generate() =\n%s\nwant no vtable for Uploader", got)
+	}
+	if !strings.Contains(got, "type UploaderFuncs struct") {
+		t.Errorf("Generate() =\n%s\nwant the adapter of Uploader", got)
+	}
+
+	want := "skipping vtable for Uploader because its function types have parameters widened with --widen-params"
+	if got := logged(); !slices.Contains(got, want) {
+		t.Errorf("warnings = %q, want %q", got, want)
+	}
+}
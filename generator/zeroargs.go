@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// stringifyZeroArgs will take an interface method and emit a Zero<Method>Args function returning the zero value of each of its parameters, in order.
+// Returns an empty string if the method has no parameters.
+func (r *renderer) stringifyZeroArgs(meth *types.Func, typeParams typeParamLists) string {
+	sig, ok := meth.Type().Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() == 0 {
+		return ""
+	}
+
+	reserved := make(map[string]bool)
+	var resultTypes, values, zeroDecls []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i).Type()
+		typ := r.typeString(param)
+		resultTypes = append(resultTypes, typ)
+
+		if zero := r.zeroValue(param); zero != "" {
+			values = append(values, zero)
+			continue
+		}
+
+		// Type parameters have no zero value literal, so their zero value is declared instead.
+		name := uniqueName(fmt.Sprintf("p%d", i), reserved)
+		reserved[name] = true
+		zeroDecls = append(zeroDecls, fmt.Sprintf("\tvar %s %s\n", name, typ))
+		values = append(values, name)
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("// Zero%sArgs returns the zero value of each parameter of %s, in order.\n", meth.Name(), meth.Name()))
+	builder.WriteString(fmt.Sprintf("func Zero%sArgs%s()%s {\n", meth.Name(), typeParams.decl, resultList(resultTypes)))
+	builder.WriteString(strings.Join(zeroDecls, ""))
+	builder.WriteString(fmt.Sprintf("\treturn %s\n}", strings.Join(values, ", ")))
+	return builder.String()
+}
+
+// zeroValue returns the literal for the zero value of the type, like 0, "", nil or T{}. Returns an empty string for type parameters, which have no zero value literal.
+func (r *renderer) zeroValue(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		default:
+			// unsafe.Pointer and the untyped nil.
+			return "nil"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		if _, isTypeParam := t.(*types.TypeParam); isTypeParam {
+			return ""
+		}
+		return "nil"
+	case *types.Struct, *types.Array:
+		return r.typeString(t) + "{}"
+	}
+	return ""
+}
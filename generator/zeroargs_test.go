@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateZeroArgs(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitZeroArgs: true})["testdata_functypes.go"]
+
+	want := generatedHeader + `
+
+package functypes
+
+type Abc func() (string, error)
+type Bar func(a string) error
+
+// ZeroBarArgs returns the zero value of each parameter of Bar, in order.
+func ZeroBarArgs() string {
+	return ""
+}
+
+type Foo func(a string, b int, c ...string)
+
+// ZeroFooArgs returns the zero value of each parameter of Foo, in order.
+func ZeroFooArgs() (string, int, []string) {
+	return "", 0, nil
+}
+`
+	if got != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateZeroArgsOfTypeParams(t *testing.T) {
+	got := generateFiles(t, Config{PkgPath: "../testdata/ordered", EmitZeroArgs: true})["ordered_functypes.go"]
+
+	// A type parameter has no zero value literal, so its zero value is declared.
+	want := "func ZeroLookupArgs[K comparable]() K {\n\tvar p0 K\n\treturn p0\n}\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() =\n%s\nwant it to contain\n%s", got, want)
+	}
+}
+
+func TestGenerateZeroArgsCompileAndReturnZeros(t *testing.T) {
+	goTestGenerated(t, Config{PkgPath: "../testdata", Include: "^MyInterface$", EmitZeroArgs: true}, map[string]string{
+		"zeroargs_test.go": `package functypes
+
+import "testing"
+
+func TestZeroArgs(t *testing.T) {
+	if a := ZeroBarArgs(); a != "" {
+		t.Errorf("ZeroBarArgs() = %q, want the zero value", a)
+	}
+	if a, b, c := ZeroFooArgs(); a != "" || b != 0 || c != nil {
+		t.Errorf("ZeroFooArgs() = %q, %d, %v, want the zero values", a, b, c)
+	}
+}
+`,
+	})
+	goTestGenerated(t, Config{PkgPath: "../testdata/ordered", EmitZeroArgs: true}, map[string]string{
+		"zeroargs_test.go": `package functypes
+
+import "testing"
+
+func TestZeroArgs(t *testing.T) {
+	if k := ZeroLookupArgs[string](); k != "" {
+		t.Errorf("ZeroLookupArgs() = %q, want the zero value", k)
+	}
+	if items := ZeroSortArgs[int](); items != nil {
+		t.Errorf("ZeroSortArgs() = %v, want nil", items)
+	}
+}
+`,
+	})
+}
@@ -1,13 +1,14 @@
 module github.com/eaardal/functypes
 
-go 1.19
+go 1.25.0
 
 require (
 	github.com/sirupsen/logrus v1.9.0
-	golang.org/x/tools v0.5.0
+	golang.org/x/tools v0.44.0
 )
 
 require (
-	golang.org/x/mod v0.7.0 // indirect
-	golang.org/x/sys v0.4.0 // indirect
+	golang.org/x/mod v0.35.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
 )
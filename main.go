@@ -3,174 +3,282 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/eaardal/functypes/generator"
 	"github.com/sirupsen/logrus"
-	"go/types"
-	"golang.org/x/tools/go/packages"
 	"os"
-	"path"
-	"path/filepath"
+	"sort"
 	"strings"
 )
 
-const (
-	dirPerm  = 0750
-	filePerm = 0666
-)
-
-var pkgPath = flag.String("pkg-path", ".", "the path to a Go package containing .go files")
+var pkgPath = flag.String("pkg-path", "", "the path to a Go package containing .go files (defaults to the current directory, which is the package's directory when run by go generate)")
+var fromGoList = flag.Bool("from-go-list", false, "scan the packages listed by the output of go list -json read from stdin (like go list -json ./... | functypes --from-go-list) instead of --pkg-path")
+var jobs = flag.Int("jobs", 1, "how many of the packages matched by a package pattern or --from-go-list are rendered at a time, the output is the same for any number")
 var outputDirPath = flag.String("out-dir", "functypes", "the full path to the directory where the function types should be stored")
+var subpackages = flag.Bool("subpackages", false, "write the function types of each interface into its own subpackage of --out-dir named after the interface (like functypes/reader for Reader), plus an aggregator in --out-dir re-exporting their types with aliases")
+var route = flag.String("route", "", "semicolon separated <pattern>=<dir> entries writing the function types of interfaces with a name matching the glob pattern to another output directory, like Repo*=./repos;Svc*=./services")
+var dryRun = flag.Bool("dry-run", false, "print the files that would be generated to stdout instead of writing them")
+var importsOnly = flag.Bool("imports-only", false, "print only the import block each generated file would have to stdout, for splicing into hand-written files, instead of writing the files")
 var verbose = flag.Bool("verbose", false, "show verbose log output?")
-
-var cfg = &packages.Config{
-	Mode:       packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedTypesInfo | packages.NeedTypes,
-	Context:    nil,
-	Logf:       nil,
-	Dir:        "",
-	Env:        nil,
-	BuildFlags: nil,
-	Fset:       nil,
-	ParseFile:  nil,
-	Tests:      false,
-	Overlay:    nil,
-}
+var logFormat = flag.String("log-format", "text", "the format of the log output: text, or json for a JSON object per line, whose added: lines carry the interface, method and functype fields for CI to parse")
+var exportFile = flag.String("export-file", "", "load the package from this compiled export data file (like a .a archive) instead of from source, with --pkg-path as the package's import path")
+var overlay = flag.String("overlay", "", "a JSON file replacing the contents of source files while loading the packages, like the -overlay flag of go build")
+var seedFile = flag.String("seed-file", "", "the .go file in --pkg-path used to load the package, instead of the first .go file found in the directory")
+var onlyFile = flag.String("file", "", "only convert interfaces declared in this file, given as a file name (like foo.go) or a path")
+var include = flag.String("include", "", "only convert interfaces with a name matching this regular expression")
+var exclude = flag.String("exclude", "", "skip interfaces with a name matching this regular expression")
+var includeMethods = flag.String("include-methods", "", "only convert methods with a name matching this regular expression")
+var excludeMethods = flag.String("exclude-methods", "", "skip methods with a name matching this regular expression")
+var method = flag.String("method", "", "only convert the methods with this name, across all interfaces, failing if their signatures differ")
+var fromType = flag.String("from-type", "", "only convert the interfaces this struct type depends on through its fields (by name, or <import path>.<TypeName>), including those of the structs it holds up to --from-type-depth levels deep, wherever they're declared")
+var fromTypeDepth = flag.Int("from-type-depth", 3, "how many levels of struct fields --from-type looks for interfaces in: 1 for the type's own fields, 2 to include the fields of the structs it holds, and so on")
+var getters = flag.Bool("getters", false, "only convert methods shaped like getters: no parameters and at least one result (combine with --setters to convert both)")
+var setters = flag.Bool("setters", false, "only convert methods shaped like setters: at least one parameter, and no results or only an error (combine with --getters to convert both)")
+var onlyImplemented = flag.Bool("only-implemented", false, "only convert interfaces which at least one type declared in the scanned packages implements (use a pattern like ./... to scan the implementations along with the interfaces)")
+var caseInsensitive = flag.Bool("case-insensitive", false, "match the --include/--exclude expressions case-insensitively")
+var failOnUnexportedMethods = flag.Bool("fail-on-unexported-methods", false, "fail when an interface has unexported methods, instead of skipping them with a warning")
+var skipUnexportedMethods = flag.Bool("skip-unexported-methods", false, "skip unexported interface methods without a warning")
+var exportUnexported = flag.Bool("export-unexported", false, "convert unexported interface methods too, to exported function types with the method name capitalized")
+var emitter = flag.String("emitter", "go", "the emitter writing the generated files: go, or a custom emitter registered with generator.RegisterEmitter by a build of functypes")
+var emitResultStructs = flag.Bool("emit-result-structs", false, "also emit a <Name>Result struct for methods returning more than one value")
+var emitAdapter = flag.Bool("emit-adapter", false, "also emit a <Interface>Funcs struct per interface which implements the interface by delegating to function type fields")
+var adapters = flag.Bool("adapters", false, "same as --emit-adapter")
+var adapterReceiver = flag.String("adapter-receiver", "pointer", "the receivers of the --emit-adapter methods: pointer (the fields can be changed after the adapter is passed along), value (the struct itself implements the interface) or both (an adapter of each, the one with value receivers named <Interface>ValueFuncs)")
+var emitVTable = flag.Bool("emit-vtable", false, "also emit a <Interface>VTable struct per interface with a function type field per method, and a Populate method filling it from an implementation, like a C-style vtable for plugin boundaries")
+var emitTest = flag.Bool("emit-test", false, "also write a <pkg>_functypes_test.go asserting that the adapters implement their source interfaces (implies --emit-adapter)")
+var emitExamples = flag.Bool("emit-examples", false, "also write a <pkg>_functypes_test.go with an Example<Name> testable example per function type, as a starting point for documenting them")
+var emitNilChecks = flag.Bool("emit-nil-checks", false, "also emit an IsSet method per function type, reporting whether a function was assigned to it, handy in option validation")
+var emitMust = flag.Bool("emit-must", false, "also emit a Must<Name> wrapper for function types returning an error, which panics instead of returning the error")
+var emitErrorVars = flag.Bool("emit-error-vars", false, "also emit a sentinel Err<Interface><Method> var per method returning an error (of the --error-type), a nil placeholder for tests to assign the errors to return or expect")
+var emitCtxGuard = flag.Bool("emit-ctx-guard", false, "also emit a Guard<Name> wrapper for function types taking a context.Context first and returning an error, which returns the context's error instead of calling the function once the context is done")
+var emitChain = flag.Bool("emit-chain", false, "also emit a <Name>Middleware type decorating each function type, and a Chain<Name> combinator applying middlewares in order")
+var emitZeroArgs = flag.Bool("emit-zero-args", false, "also emit a Zero<Name>Args function per function type, returning the zero value of each of its parameters")
+var emitInit = flag.Bool("emit-init", false, "also emit an init function registering each function type by name with the --register-func")
+var registerFunc = flag.String("register-func", "", "the func(name string, value any) function --emit-init registers each function type with, in the form <import path>.<FuncName>")
+var emitStubs = flag.Bool("emit-stubs", false, "also emit a Stub<Name> function per function type, returning an implementation which does nothing and returns zero values")
+var stubError = flag.String("stub-error", "nil", "what the stubs return as the error: nil or sentinel (a generated ErrNotImplemented, detectable with errors.Is)")
+var errorTypeName = flag.String("error-type", "", "the type to treat as the error result, in the form <import path>.<TypeName> (defaults to the builtin error)")
+var provenance = flag.Bool("provenance", false, "add a comment to each function type naming the interface and package it was generated from")
+var provenanceSource = flag.String("provenance-source", "embedder", "which interface --provenance attributes methods inherited through an embedded interface to: embedder (the interface embedding it) or definer (the interface declaring it)")
+var replaceType = flag.String("replace-type", "", "comma separated <old type>=<new type> entries rendering the old type as the new one, which must be assignable to or from it, with types in the form [*]<import path>.<TypeName> (like *example.com/x.File=example.com/x.Named)")
+var inlinePackage = flag.String("inline-package", "", "comma separated import paths of packages whose types referenced by the generated code are copied into the generated files instead of imported (along with the types of the same packages they refer to), for self-contained output")
+var typeAlias = flag.Bool("type-alias", false, "render the function types as aliases, like type Read = func(p []byte) (n int, err error), which are identical to the function signature rather than a distinct type")
+var noParamNames = flag.Bool("no-param-names", false, "drop the parameter and result names from the function types, like func([]byte) (int, error), so renaming them doesn't change the generated code")
+var nameTemplate = flag.String("name-template", "", "a text/template rendering the name of each function type from {{.Interface}}, {{.Method}} and {{.Package}}, like {{.Interface}}{{.Method}} for ReaderRead, used as it is rather than prefixed to keep the names unique")
+var flattenUnique = flag.Bool("flatten-unique", false, "only prefix the function types of the methods converted for several interfaces with the interface name, like ReadCloserClose, instead of all methods of those interfaces, so the others keep short names like Read")
+var methodExpressions = flag.Bool("method-expressions", false, "render the function types with the interface as their first parameter, like type Read func(r Reader, p []byte) (n int, err error), so a method expression like Reader.Read can be assigned to them")
+var samePackage = flag.Bool("same-package", false, "render the generated file as part of the scanned package, like --qualify-relative-to with its import path, into its directory unless --out-dir is given")
+var qualifyRelativeTo = flag.String("qualify-relative-to", "", "render the generated files as part of the package with this import path: its types are referenced without qualifier or import, and the files declare its package name (for --out-dir pointing at that package's directory)")
+var expandAliases = flag.Bool("expand-aliases", false, "render type aliases (like type MyInt = int) as the types they denote instead of by their alias name")
+var aggregateMode = flag.String("aggregate-mode", "expand", "how to render interfaces which only embed other interfaces (like type All interface { A; B }): expand (a function type per method), skip (nothing) or reference (only the methods of embedded interfaces which aren't rendered themselves)")
+var widenParams = flag.Bool("widen-params", false, "replace struct typed parameters in function types with the narrowest interface from the scanned package that the struct implements")
+var importsReport = flag.String("imports-report", "", "write the packages referenced by the generated function types to this file, with the number of function types referencing each, for auditing dependencies")
+var emitSummary = flag.String("emit-summary", "", "write a Markdown table of each interface, its methods and the generated function types to this file, for documentation and reviewing the generated surface")
+var explain = flag.String("explain", "", "log the rendering decisions for each method of the named interface: its raw signature, how referenced packages are qualified and imported, and the rendered declaration")
+var validate = flag.Bool("validate", false, "type check the generated files before writing them, failing with the first type error instead of writing code that doesn't compile")
+var maxInterfaces = flag.Int("max-interfaces", 0, "fail when more than this many interfaces are processed, as a safety valve against scanning a huge tree by accident with a pattern like ./... (0 means no limit)")
+var astFallback = flag.Bool("ast-fallback", false, "like --best-effort, but render the methods referencing unresolved types from their source instead of skipping them")
+var bestEffort = flag.Bool("best-effort", false, "generate what can be resolved when packages fail to load (e.g. missing dependencies), skipping methods referencing unresolved types")
+var force = flag.Bool("force", false, "overwrite existing output files even if they were not generated by functypes")
+var forceGlob = flag.String("force-glob", "", "comma separated glob patterns of output paths to overwrite even if they were not generated by functypes")
+var protectGlob = flag.String("protect-glob", "", "comma separated glob patterns of output paths which are never overwritten unless they were generated by functypes, even with --force")
+var emptyInterfaceStyle = flag.String("empty-interface", "any", "how to write the empty interface in the generated code: any or interface{}")
+var update = flag.String("update", "", "comma separated names of interfaces to regenerate within the existing output files, keeping all other declarations byte for byte")
+var compatWith = flag.String("compat-with", "", "path to a previously generated file; function types that were renamed or removed since then are kept as deprecated declarations")
+var lock = flag.String("lock", "", "record the generated interfaces and the hashes of their signatures in this lock file")
+var keepAliases = flag.Bool("keep-aliases", false, "emit a deprecated alias from the old function type name to the new one when a method is renamed, detected by its signature recorded in the --lock file, so downstream code keeps compiling")
+var frozen = flag.Bool("frozen", false, "fail when an interface is generated that isn't listed in the --lock file, instead of adding it, to prevent the generated code from growing by accident")
+var adopt = flag.String("adopt", "", "a hand-written .go file of function types to match against the generated ones by signature, reporting which of them functypes can manage and under what names, instead of generating")
+var clean = flag.Bool("clean", false, "delete the files previously generated in --out-dir (as listed in its .functypes-manifest) instead of generating, refusing files without the generated header")
 
 func main() {
 	flag.Parse()
 
+	if err := applyEnvFallbacks(flag.CommandLine); err != nil {
+		logrus.Fatal(err)
+	}
+
 	if verbose != nil && *verbose {
 		logrus.SetLevel(logrus.DebugLevel)
 	} else {
 		logrus.SetLevel(logrus.InfoLevel)
 	}
 
-	if pkgPath == nil || *pkgPath == "" {
-		logrus.Fatalf("--pkg-path is required")
+	switch *logFormat {
+	case "text":
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		logrus.Fatalf("--log-format must be text or json, got %q", *logFormat)
 	}
 
 	if outputDirPath == nil || *outputDirPath == "" {
 		logrus.Fatalf("--out-file is required")
 	}
-
-	pkgName := filepath.Base(*pkgPath)
-
-	fileName, err := firstGoFileInDirectory(*pkgPath)
-	if err != nil {
-		logrus.Fatal(err)
+	outDir := *outputDirPath
+	// The generator defaults a FromTypeDepth of 0 to the flag's default, which would silently ignore --from-type-depth 0.
+	if isFlagSet("from-type-depth") && *fromTypeDepth == 0 {
+		logrus.Fatalf("--from-type-depth must be at least 1, got 0")
 	}
-
-	filePath := path.Join(*pkgPath, fileName)
-	logrus.Debugf("filePath: %s", filePath)
-
-	pkgs, err := packages.Load(cfg, "file="+filePath)
-	if err != nil {
-		logrus.Fatal(err)
+	// Likewise for a Jobs of 0.
+	if *jobs == 0 {
+		logrus.Fatalf("--jobs must be at least 1, got 0")
+	}
+	// With --same-package the generator defaults the output directory to the package's directory instead.
+	if *samePackage && !isFlagSet("out-dir") {
+		outDir = ""
 	}
-	logrus.Debugf("packages loaded: %+v", pkgs)
-
-	outputBuilder := &strings.Builder{}
-	outputBuilder.WriteString(packageLine())
 
-	if err := processPackages(pkgs, outputBuilder); err != nil {
-		logrus.Fatal(err)
+	cfg := generator.Config{
+		PkgPath:                 *pkgPath,
+		OutDir:                  outDir,
+		FromGoList:              *fromGoList,
+		Jobs:                    *jobs,
+		Route:                   *route,
+		Subpackages:             *subpackages,
+		ExportFile:              *exportFile,
+		Overlay:                 *overlay,
+		SeedFile:                *seedFile,
+		File:                    *onlyFile,
+		Include:                 *include,
+		Exclude:                 *exclude,
+		IncludeMethods:          *includeMethods,
+		ExcludeMethods:          *excludeMethods,
+		Method:                  *method,
+		FromType:                *fromType,
+		FromTypeDepth:           *fromTypeDepth,
+		Getters:                 *getters,
+		Setters:                 *setters,
+		OnlyImplemented:         *onlyImplemented,
+		CaseInsensitive:         *caseInsensitive,
+		FailOnUnexportedMethods: *failOnUnexportedMethods,
+		SkipUnexportedMethods:   *skipUnexportedMethods,
+		ExportUnexported:        *exportUnexported,
+		Emitter:                 *emitter,
+		EmitResultStructs:       *emitResultStructs,
+		EmitAdapter:             *emitAdapter || *adapters,
+		AdapterReceiver:         *adapterReceiver,
+		EmitVTable:              *emitVTable,
+		EmitTest:                *emitTest,
+		EmitExamples:            *emitExamples,
+		EmitNilChecks:           *emitNilChecks,
+		EmitMust:                *emitMust,
+		EmitErrorVars:           *emitErrorVars,
+		EmitCtxGuard:            *emitCtxGuard,
+		EmitChain:               *emitChain,
+		EmitZeroArgs:            *emitZeroArgs,
+		EmitInit:                *emitInit,
+		RegisterFunc:            *registerFunc,
+		EmitStubs:               *emitStubs,
+		StubError:               *stubError,
+		ErrorType:               *errorTypeName,
+		Provenance:              *provenance,
+		ProvenanceSource:        *provenanceSource,
+		ReplaceType:             *replaceType,
+		InlinePackage:           *inlinePackage,
+		NoParamNames:            *noParamNames,
+		NameTemplate:            *nameTemplate,
+		FlattenUnique:           *flattenUnique,
+		TypeAlias:               *typeAlias,
+		MethodExpressions:       *methodExpressions,
+		QualifyRelativeTo:       *qualifyRelativeTo,
+		SamePackage:             *samePackage,
+		ExpandAliases:           *expandAliases,
+		AggregateMode:           *aggregateMode,
+		WidenParams:             *widenParams,
+		ImportsReport:           *importsReport,
+		EmitSummary:             *emitSummary,
+		Explain:                 *explain,
+		Validate:                *validate,
+		MaxInterfaces:           *maxInterfaces,
+		BestEffort:              *bestEffort,
+		ASTFallback:             *astFallback,
+		Force:                   *force,
+		ForceGlob:               *forceGlob,
+		ProtectGlob:             *protectGlob,
+		EmptyInterface:          *emptyInterfaceStyle,
+		Lock:                    *lock,
+		KeepAliases:             *keepAliases,
+		Frozen:                  *frozen,
+		ImportsOnly:             *importsOnly,
+		Adopt:                   *adopt,
+		Clean:                   *clean,
+		Update:                  *update,
+		CompatWith:              *compatWith,
 	}
 
-	outFileName := fmt.Sprintf("%s_functypes.go", pkgName)
-	outFilePath := path.Join(*outputDirPath, outFileName)
-	logrus.Debugf("outFilePath: %s", outFilePath)
+	if *dryRun || *importsOnly {
+		printRendered(cfg)
+		return
+	}
 
-	if err := writeOutput(outFilePath, []byte(outputBuilder.String())); err != nil {
+	if err := generator.Generate(cfg); err != nil {
 		logrus.Fatal(err)
 	}
-	logrus.Infof("saved %s", outFilePath)
 }
 
-// firstGoFileInDirectory returns the name of the first .go file it finds in the given directory path.
-// Because package.Load requires a .go file which it'll use to inspect that file's package, the name of any .go file in the given directory will do, so we just grab the first.
-func firstGoFileInDirectory(dir string) (string, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return "", fmt.Errorf("failed to read directory %s: %v", dir, err)
-	}
-
-	logrus.Debugf("found %d entries in directory %s", len(entries), *pkgPath)
-
-	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".go") {
-			continue
+// envPrefix is the prefix of the environment variables flags fall back to, see applyEnvFallbacks.
+const envPrefix = "FUNCTYPES_"
+
+// applyEnvFallbacks sets each flag of the set that wasn't given on the command line from its environment variable, if set. The variable is named after the flag, like FUNCTYPES_OUT_DIR for --out-dir.
+// Flags given on the command line always take precedence, which makes it easy to configure containerized invocations through the environment.
+func applyEnvFallbacks(flags *flag.FlagSet) error {
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if given[f.Name] || err != nil {
+			return
 		}
 
-		return entry.Name(), nil
-	}
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
 
-	return "", fmt.Errorf("found no .go files in %s", dir)
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %v", value, name, setErr)
+		}
+	})
+	return err
 }
 
-// processPackages iterates through each package and continues to investigate each occurrance in its Scope.
-// The entries found in pkg.Types.Scope is determined based on the Mode filter in packages.Config (see cfg at the top of the file).
-func processPackages(pkgs []*packages.Package, outputBuilder *strings.Builder) error {
-	for _, pkg := range pkgs {
-		scope := pkg.Types.Scope()
-		logrus.Debugf("%s scope: %v", pkg.PkgPath, scope.Names())
-
-		// Because we've included packages.NeedTypesInfo and packages.NeedTypes in packages.Config at the top of the file, scope.Names includes the types found based on those criteria (based on all criterias in the cfg.Mode field).
-		for _, scopeName := range scope.Names() {
-			processInterfacesInScope(scope, scopeName, outputBuilder)
+// isFlagSet returns true if the flag with the given name was set, on the command line or through its environment variable.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
-	}
-	return nil
+	})
+	return set
 }
 
-// processInterfacesInScope will look up the named object in the package's scope and check if it's an interface. If it is, it calls further down to extract the interface's methods.
-func processInterfacesInScope(scope *types.Scope, scopeName string, builder *strings.Builder) {
-	obj := scope.Lookup(scopeName)
-
-	named, ok := obj.Type().(*types.Named)
-	if !ok {
-		return
+// printRendered implements --dry-run and --imports-only: it renders the files (or their import blocks) with generator.Render and prints each of them to stdout, preceded by its path.
+// A single import block is printed as is, so it can be piped straight into a file.
+func printRendered(cfg generator.Config) {
+	files, err := generator.Render(cfg)
+	if err != nil {
+		logrus.Fatal(err)
 	}
 
-	iface, ok := named.Underlying().(*types.Interface)
-	if !ok {
+	if cfg.ImportsOnly && len(files) == 1 {
+		for _, content := range files {
+			fmt.Print(string(content))
+		}
 		return
 	}
 
-	appendInterfaceMethodsToBuilder(iface, builder)
-}
-
-// appendInterfaceMethodsToBuilder will iterate through each method on the interface and stringify its signature into a standalone function type, then append that signature to the string builder.
-func appendInterfaceMethodsToBuilder(iface *types.Interface, builder *strings.Builder) {
-	for i := 0; i < iface.NumMethods(); i++ {
-		method := stringifyInterfaceMethod(iface.Method(i))
-		builder.WriteString(method + "\n")
-		logrus.Infof("added: %s", method)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
 	}
-}
+	sort.Strings(paths)
 
-// stringifyInterfaceMethod will take the signature of an interface's method and convert it to a standalone function type with the same signature.
-func stringifyInterfaceMethod(meth *types.Func) string {
-	sig, ok := meth.Type().Underlying().(*types.Signature)
-	if !ok {
-		return ""
+	for _, path := range paths {
+		fmt.Printf("==> %s <==\n%s\n", path, files[path])
 	}
-	return fmt.Sprintf("type %s %s", meth.Name(), sig.String())
-}
-
-// packageLine returns the package header line required for all .go files. This will be the first line of all output files written by this app.
-func packageLine() string {
-	return fmt.Sprintf("package functypes\n\n")
-}
-
-// writeOutput will ensure the directories to the output file exists and create the output file. If the file exists, it will be overwritten.
-func writeOutput(outFilePath string, content []byte) error {
-	dirPath := filepath.Dir(outFilePath)
-
-	if err := os.MkdirAll(dirPath, dirPerm); err != nil {
-		return fmt.Errorf("mkdir %s with perm %d: %w", dirPath, dirPerm, err)
-	}
-
-	if err := os.WriteFile(outFilePath, content, filePerm); err != nil {
-		return fmt.Errorf("write %s with perm %d: %w", outFilePath, filePerm, err)
-	}
-
-	return nil
 }
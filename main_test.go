@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestApplyEnvFallbacks(t *testing.T) {
+	flags := flag.NewFlagSet("functypes", flag.ContinueOnError)
+	outDir := flags.String("out-dir", "functypes", "")
+	pkgPath := flags.String("pkg-path", "", "")
+	dryRun := flags.Bool("dry-run", false, "")
+	verbose := flags.Bool("verbose", false, "")
+
+	t.Setenv("FUNCTYPES_OUT_DIR", "from-env")
+	t.Setenv("FUNCTYPES_PKG_PATH", "./from-env")
+	t.Setenv("FUNCTYPES_DRY_RUN", "true")
+
+	if err := flags.Parse([]string{"--pkg-path", "./from-flag"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyEnvFallbacks(flags); err != nil {
+		t.Fatalf("applyEnvFallbacks() error = %v", err)
+	}
+
+	if *outDir != "from-env" {
+		t.Errorf("--out-dir = %q, want %q", *outDir, "from-env")
+	}
+	if *pkgPath != "./from-flag" {
+		t.Errorf("--pkg-path = %q, want the flag to take precedence, %q", *pkgPath, "./from-flag")
+	}
+	if !*dryRun {
+		t.Errorf("--dry-run = false, want true")
+	}
+	if *verbose {
+		t.Errorf("--verbose = true, want the default false")
+	}
+}
+
+func TestApplyEnvFallbacksInvalidValue(t *testing.T) {
+	flags := flag.NewFlagSet("functypes", flag.ContinueOnError)
+	flags.Bool("dry-run", false, "")
+
+	t.Setenv("FUNCTYPES_DRY_RUN", "maybe")
+
+	err := applyEnvFallbacks(flags)
+	if want := `invalid value "maybe" for FUNCTYPES_DRY_RUN: parse error`; err == nil || err.Error() != want {
+		t.Errorf("applyEnvFallbacks() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,12 @@
+// Package foo lives in a directory whose name isn't a valid package name.
+package foo
+
+// Fetcher fetches a value by key.
+type Fetcher interface {
+	Fetch(key string) (Value, error)
+}
+
+// Value is a fetched value.
+type Value struct {
+	Data []byte
+}
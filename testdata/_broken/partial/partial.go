@@ -0,0 +1,9 @@
+package partial
+
+import "github.com/eaardal/functypes/testdata/does/not/exist"
+
+type Service interface {
+	Ping() error
+	Fetch(id string) (exist.Thing, error)
+	Name() string
+}
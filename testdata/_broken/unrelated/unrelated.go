@@ -0,0 +1,22 @@
+// Package unrelated fails to compile for reasons unrelated to its interface: a missing dependency and a type error in a function.
+// With --ast-fallback, Store's Get is rendered from its source, and Put from its types as usual.
+package unrelated
+
+import (
+	"io"
+
+	missing "github.com/eaardal/functypes/testdata/does/not/exist"
+)
+
+type Value struct {
+	Data []byte
+}
+
+type Store interface {
+	Get(key string, opts ...missing.Option) (*missing.Thing, map[string]Value, error)
+	Put(key string, value Value, w io.Writer) error
+}
+
+func broken() int {
+	return "not an int"
+}
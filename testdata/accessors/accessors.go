@@ -0,0 +1,11 @@
+package accessors
+
+// Settings has getters, setters and methods which are neither.
+type Settings interface {
+	Name() string
+	Size() (int, error)
+	SetName(name string)
+	SetSize(size int) error
+	Resize(delta int) (int, error)
+	Reset()
+}
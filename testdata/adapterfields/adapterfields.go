@@ -0,0 +1,7 @@
+// Package adapterfields has an interface whose adapter can't hold its methods in fields named after them: the field FindFunc of Find would collide with the method FindFunc.
+package adapterfields
+
+type Index interface {
+	Find(key string) (string, bool)
+	FindFunc(match func(key string) bool) []string
+}
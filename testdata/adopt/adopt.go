@@ -0,0 +1,11 @@
+package adopt
+
+type Order struct {
+	ID    string
+	Total int
+}
+
+type OrderStore interface {
+	Save(order Order) error
+	Find(id string) (Order, bool)
+}
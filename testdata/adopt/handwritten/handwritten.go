@@ -0,0 +1,13 @@
+// Package handwritten declares function types by hand, which --adopt matches to the methods of adopt.OrderStore by signature.
+package handwritten
+
+import "github.com/eaardal/functypes/testdata/adopt"
+
+// Save matches OrderStore.Save under the same name.
+type Save func(order adopt.Order) error
+
+// FindOrder matches OrderStore.Find, whose function type is named Find.
+type FindOrder func(orderID string) (adopt.Order, bool)
+
+// Cancel matches no method.
+type Cancel func(id string) error
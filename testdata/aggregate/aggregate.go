@@ -0,0 +1,18 @@
+package aggregate
+
+import "io"
+
+type Getter interface {
+	Get(key string) (string, error)
+}
+
+type Putter interface {
+	Put(key, value string) error
+}
+
+// Store only aggregates other interfaces. With --aggregate-mode=expand its Get and Put duplicate those of Getter and Putter as StoreGet and StorePut, along with StoreClose, with reference only Close from io.Closer is rendered for it, and with skip nothing is.
+type Store interface {
+	Getter
+	Putter
+	io.Closer
+}
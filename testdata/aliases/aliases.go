@@ -0,0 +1,21 @@
+package aliases
+
+import "time"
+
+// MyInt is an alias, so it renders as MyInt by default and as int with --expand-aliases.
+type MyInt = int
+
+// Timeout is an alias of a type from another package, which renders as time.Duration with --expand-aliases.
+type Timeout = time.Duration
+
+type Counter interface {
+	Add(delta MyInt) MyInt
+	Wait(timeout Timeout) error
+}
+
+// Set is a generic alias, so its instantiations keep their type arguments, like Set[string], unless expanded to map[string]bool.
+type Set[T comparable] = map[T]bool
+
+type Index interface {
+	Keys(set Set[string]) []string
+}
@@ -0,0 +1,7 @@
+package testdata
+
+type AnyStore interface {
+	Put(key string, value any)
+	Get(key string) interface{}
+	Each(fn func(key string, value any) bool)
+}
@@ -0,0 +1,8 @@
+package blanks
+
+// Scanner mixes blank and other names, which is kept as is, while names which are all blank are dropped.
+type Scanner interface {
+	Scan(_ []byte, n int) (_ int, err error)
+	Skip(_ int) (_ int, _ error)
+	Close() (_ error)
+}
@@ -0,0 +1,9 @@
+package cache
+
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+type Entry[T any] struct {
+	Value T
+}
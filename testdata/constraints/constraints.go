@@ -0,0 +1,12 @@
+package constraints
+
+// Number is a custom constraint for the generic interfaces in testdata/ordered.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Stringer is a constraint with a method, to check constraints that aren't just type sets.
+type Stringer interface {
+	comparable
+	String() string
+}
@@ -0,0 +1,11 @@
+package ctxguard
+
+import "context"
+
+// Fetcher's Fetch and Ping get a Guard with --emit-ctx-guard, while Log doesn't return an error and Count doesn't take a context first.
+type Fetcher interface {
+	Fetch(ctx context.Context, id string) ([]byte, int, error)
+	Ping(context.Context) error
+	Log(ctx context.Context, message string)
+	Count(id string, ctx context.Context) (int, error)
+}
@@ -0,0 +1,3 @@
+package x
+
+type ID string
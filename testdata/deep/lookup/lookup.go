@@ -0,0 +1,13 @@
+package lookup
+
+import (
+	alpha "github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	beta "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+	gamma "github.com/eaardal/functypes/testdata/deep/gamma/pkg/platform/storage/x"
+)
+
+// Resolver references three packages named x. Their aliases are assigned in import path order (x, x2 and x3), regardless of the order they're referenced in.
+type Resolver interface {
+	A(id gamma.ID) beta.ID
+	B(id alpha.ID) gamma.ID
+}
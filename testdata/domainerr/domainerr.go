@@ -0,0 +1,20 @@
+package domainerr
+
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+type Store interface {
+	Save(key string, value string) *Error
+	Load(key string) (string, *Error)
+}
+
+// Syncer returns both the custom error type and the builtin error. Only the custom one is qualified and imported.
+type Syncer interface {
+	Sync(key string) (conflict *Error, err error)
+}
@@ -0,0 +1,15 @@
+package embedded
+
+import "io"
+
+type Closer interface {
+	Close() error
+}
+
+// ReadCloser inherits Close from Closer in this package and Read from io.Reader, which --provenance-source decides the attribution of.
+// Both interfaces have a Close method, whose function types are named CloserClose and ReadCloserClose to keep them unique. Read and Name get the prefix too, or keep their names with --flatten-unique.
+type ReadCloser interface {
+	io.Reader
+	Closer
+	Name() string
+}
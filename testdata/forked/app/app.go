@@ -0,0 +1,8 @@
+// Package app uses a dependency replaced with a local fork. The generated code imports it by its original path example.com/upstream, while its types are loaded from the fork.
+package app
+
+import "example.com/upstream"
+
+type Publisher interface {
+	Publish(event upstream.Event) error
+}
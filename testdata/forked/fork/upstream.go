@@ -0,0 +1,8 @@
+// Package upstream stands in for a local fork of the example.com/upstream dependency, which the forked module points at with a replace directive.
+package upstream
+
+type Event struct {
+	Name string
+	// Forked is only declared by the fork.
+	Forked bool
+}
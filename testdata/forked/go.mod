@@ -0,0 +1,7 @@
+module example.com/forked
+
+go 1.25.0
+
+require example.com/upstream v1.0.0
+
+replace example.com/upstream => ./fork
@@ -0,0 +1,35 @@
+// Package fromtype has a Service whose fields drive --from-type Service: it depends on Store and Clock directly, on io.Writer through its Logger struct, and on Notifier through a slice. Unused isn't one of its fields, so it's not converted.
+package fromtype
+
+import (
+	"io"
+	"time"
+)
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+type Notifier interface {
+	Notify(msg string) error
+}
+
+type Unused interface {
+	Nothing()
+}
+
+type Logger struct {
+	Out   io.Writer
+	Level int
+}
+
+type Service struct {
+	Store     Store
+	Clock     Clock
+	Log       *Logger
+	Notifiers []Notifier
+}
@@ -0,0 +1,8 @@
+package generics
+
+import "github.com/eaardal/functypes/testdata/cache"
+
+type CacheUser interface {
+	Use(c *cache.Cache[string, int]) error
+	Nested(c *cache.Cache[string, *cache.Entry[[]byte]]) []*cache.Entry[int]
+}
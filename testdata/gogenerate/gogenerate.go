@@ -0,0 +1,8 @@
+package gogenerate
+
+// Run go generate in this directory: functypes defaults --pkg-path to the package's directory and names the output file after $GOPACKAGE.
+//go:generate go run github.com/eaardal/functypes --out-dir functypes
+
+type Notifier interface {
+	Notify(message string) error
+}
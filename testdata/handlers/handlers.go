@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/eaardal/functypes/testdata/deep/alpha/pkg/platform/storage/x"
+	x2 "github.com/eaardal/functypes/testdata/deep/beta/pkg/platform/storage/x"
+)
+
+type Request struct {
+	Path string
+}
+
+// Handler references packages from the standard library, two packages sharing the name x, and a type of its own package, which the generated file (in another package) imports from here.
+type Handler interface {
+	Handle(ctx context.Context, r *http.Request) error
+	Route(req Request) (string, bool)
+	Copy(from x.ID, to x2.ID) error
+}
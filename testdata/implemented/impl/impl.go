@@ -0,0 +1,17 @@
+package impl
+
+// MemStore implements implemented.Store.
+type MemStore map[string][]byte
+
+func (s MemStore) Get(key string) ([]byte, error) {
+	return s[key], nil
+}
+
+// Logger implements implemented.Notifier.
+type Logger struct {
+	Messages []string
+}
+
+func (l *Logger) Notify(message string) {
+	l.Messages = append(l.Messages, message)
+}
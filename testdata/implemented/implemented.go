@@ -0,0 +1,17 @@
+// Package implemented has interfaces implemented in another package, and one which isn't implemented anywhere.
+package implemented
+
+// Store is implemented by impl.MemStore.
+type Store interface {
+	Get(key string) ([]byte, error)
+}
+
+// Notifier is implemented by impl.Logger through a pointer receiver.
+type Notifier interface {
+	Notify(message string)
+}
+
+// Archiver isn't implemented by any type.
+type Archiver interface {
+	Archive(key string) error
+}
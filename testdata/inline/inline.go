@@ -0,0 +1,10 @@
+// Package inline has an interface referencing the types of the model package, for --inline-package.
+package inline
+
+import "github.com/eaardal/functypes/testdata/inline/model"
+
+// Users looks up users.
+type Users interface {
+	GetUser(id model.ID) (*model.User, error)
+	Tagged(tag model.Tag) []model.User
+}
@@ -0,0 +1,23 @@
+// Package model has the types --inline-package copies into the generated files.
+package model
+
+import "time"
+
+// ID identifies a user.
+type ID string
+
+// User refers to other types of this package, which are inlined along with it.
+type User struct {
+	ID      ID
+	Name    string
+	Created time.Time
+	Tags    []Tag
+}
+
+// Tag labels a user.
+type Tag string
+
+// String is not copied along with the inlined Tag.
+func (t Tag) String() string {
+	return "#" + string(t)
+}
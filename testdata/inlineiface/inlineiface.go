@@ -0,0 +1,18 @@
+// Package inlineiface has methods taking and returning unnamed interface types, whose method signatures reference types of other packages and embed named interfaces.
+package inlineiface
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type Runner interface {
+	Run(job interface {
+		Do(ctx context.Context) error
+	}) error
+	Serve(h interface {
+		io.Closer
+		ServeHTTP(w http.ResponseWriter, r *http.Request)
+	}) (interface{ Done() <-chan struct{} }, error)
+}
@@ -0,0 +1,9 @@
+package secret
+
+// Token is declared in an internal package, so only packages below testdata can import it.
+type Token string
+
+// Vault's function types reference Token, so functypes warns when they're generated outside testdata.
+type Vault interface {
+	Unlock(token Token) error
+}
@@ -0,0 +1,17 @@
+// Package localctx declares an identifier named context, which collides with the name of the context package in files generated as part of it.
+package localctx
+
+import stdcontext "context"
+
+// context is the package's own notion of a context, unrelated to the standard library's.
+type context interface {
+	Lookup(key string) string
+}
+
+// Handler takes the standard library's context, which the generated files import.
+type Handler interface {
+	Handle(ctx stdcontext.Context, name string) error
+}
+
+// defaultContext is a context of this package.
+var defaultContext context
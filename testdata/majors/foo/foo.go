@@ -0,0 +1,5 @@
+package foo
+
+type Schema struct {
+	Version int
+}
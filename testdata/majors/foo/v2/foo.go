@@ -0,0 +1,7 @@
+// Package foo is the second major version of foo, declaring the same package name under the import path .../foo/v2.
+package foo
+
+type Schema struct {
+	Version int
+	Fields  []string
+}
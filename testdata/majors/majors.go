@@ -0,0 +1,11 @@
+package majors
+
+import (
+	"github.com/eaardal/functypes/testdata/majors/foo"
+	foov2 "github.com/eaardal/functypes/testdata/majors/foo/v2"
+)
+
+// Migrator references both major versions of foo, which the generated code imports as foo and foov2.
+type Migrator interface {
+	Migrate(from foo.Schema) (foov2.Schema, error)
+}
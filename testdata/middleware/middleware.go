@@ -0,0 +1,6 @@
+package middleware
+
+// Handler's function type gets a HandleMiddleware and a ChainHandle combinator with --emit-chain.
+type Handler interface {
+	Handle(path string) (status int, err error)
+}
@@ -0,0 +1,3 @@
+package consts
+
+const Version = "1.0.0"
@@ -0,0 +1,2 @@
+// Package empty only has this doc comment.
+package empty
@@ -0,0 +1,9 @@
+package models
+
+type User struct {
+	Name string
+}
+
+func (u User) String() string {
+	return u.Name
+}
@@ -0,0 +1,6 @@
+// Package services is the only package of testdata/mixed with an interface, so it's the only one getting a file with --pkg-path ./testdata/mixed/...
+package services
+
+type Mailer interface {
+	Send(to, body string) error
+}
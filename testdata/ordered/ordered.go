@@ -0,0 +1,20 @@
+package ordered
+
+import (
+	"cmp"
+
+	"github.com/eaardal/functypes/testdata/constraints"
+)
+
+// Sorter's type parameters are constrained by interfaces from other packages, which the generated type parameter lists must import.
+type Sorter[T cmp.Ordered, N constraints.Number, K constraints.Stringer] interface {
+	Sort(items []T) []T
+	Sum(values ...N) N
+	Index(keys []K) map[K]int
+}
+
+// Indexer's V is constrained by a type referring to K, so K must be declared wherever V is.
+type Indexer[K comparable, V ~[]K] interface {
+	Values() V
+	Lookup(key K) (int, error)
+}
@@ -0,0 +1,14 @@
+// Package pointers has methods with multi-level pointers to types of other packages.
+package pointers
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Resolver returns pointers of arbitrary depth, with and without result names.
+type Resolver interface {
+	Request() **http.Request
+	URL(raw string) (u ***url.URL, err error)
+	Swap(req **http.Request, headers *[]*http.Header) **[]*url.Values
+}
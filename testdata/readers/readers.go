@@ -0,0 +1,27 @@
+package readers
+
+// File, Conn and Body declare Read with the same signature, so --method Read collects them into a single function type. Queue's Read differs and collides with it, exclude it with --exclude Queue.
+type File interface {
+	Read(p []byte) (n int, err error)
+	Close() error
+}
+
+type Conn interface {
+	Read(b []byte) (int, error)
+	Write(b []byte) (int, error)
+}
+
+type Body interface {
+	Read(p []byte) (n int, err error)
+	Len() int
+}
+
+type Queue interface {
+	Read() (Message, error)
+	Ack(id string) error
+}
+
+type Message struct {
+	ID      string
+	Payload []byte
+}
@@ -0,0 +1,8 @@
+package registry
+
+// Types are the function types registered by the init functions generated with --emit-init --register-func github.com/eaardal/functypes/testdata/registry.Register.
+var Types = map[string]any{}
+
+func Register(name string, value any) {
+	Types[name] = value
+}
@@ -0,0 +1,7 @@
+// Package renamed has a method renamed since renamed.lock was written: Remove used to be Delete, so with --keep-aliases the generated Delete is kept as a deprecated alias of Remove.
+package renamed
+
+type Repository interface {
+	Get(id string) (string, error)
+	Remove(id string) error
+}
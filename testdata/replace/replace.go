@@ -0,0 +1,9 @@
+package replace
+
+import "github.com/eaardal/functypes/testdata/widen"
+
+// Store references the concrete widen.File, which can be replaced with the widen.Named interface it implements: --replace-type '*github.com/eaardal/functypes/testdata/widen.File=github.com/eaardal/functypes/testdata/widen.Named'
+type Store interface {
+	Put(f *widen.File) error
+	Get(name string) (*widen.File, error)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+)
+
+// Router returns and accepts named function types from other packages, which are referenced by name rather than by their underlying func types.
+type Router interface {
+	Handler(pattern string) http.HandlerFunc
+	Wrap(next http.Handler, after func(time.Duration)) http.HandlerFunc
+	Routes() map[string]http.HandlerFunc
+}
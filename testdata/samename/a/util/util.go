@@ -0,0 +1,6 @@
+// Package util shares its name with the util package of ../../b, so with --pkg-path ./testdata/samename/... the function types of both are rendered into one util_functypes.go.
+package util
+
+type Hasher interface {
+	Hash(b []byte) string
+}
@@ -0,0 +1,7 @@
+package util
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
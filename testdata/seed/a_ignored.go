@@ -0,0 +1,4 @@
+//go:build ignore
+
+// This file sorts first but is excluded by its build constraint, so loading the package through it fails. Use --seed-file seed.go.
+package main
@@ -0,0 +1,5 @@
+package seed
+
+type Clock interface {
+	Now() int64
+}
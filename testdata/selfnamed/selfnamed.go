@@ -0,0 +1,12 @@
+// Package selfnamed has an interface with a method of the same name as the interface.
+// By default the function type of the method is named like the interface, which collides with it when rendering into the package itself with --qualify-relative-to.
+// With --name-template '{{.Interface}}{{.Method}}' it's named ReadRead, like the prefixed name rendered into the package.
+package selfnamed
+
+type Read interface {
+	Read(p []byte) (n int, err error)
+}
+
+type Closer interface {
+	Close() error
+}
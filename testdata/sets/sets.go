@@ -0,0 +1,14 @@
+package sets
+
+// Set's T is constrained by comparable, which must be written in the type parameter lists of the generated function types as is.
+type Set[T comparable] interface {
+	Add(item T)
+	Has(item T) bool
+	Items() []T
+}
+
+// Keyed embeds comparable, so it can only be used as a constraint.
+type Keyed interface {
+	comparable
+	Key() string
+}
@@ -0,0 +1,14 @@
+// Package shadow declares a type named byte, shadowing the builtin byte in the package. Encoder uses both: the local byte in its own methods, and the builtin one through the embedded io.Writer.
+// Generated into the package with --qualify-relative-to, the local byte is referenced as byte and the builtin one as uint8.
+package shadow
+
+import "io"
+
+type byte struct {
+	bits uint8
+}
+
+type Encoder interface {
+	io.Writer
+	Encode(b byte) error
+}
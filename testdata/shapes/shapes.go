@@ -0,0 +1,18 @@
+package shapes
+
+import "github.com/eaardal/functypes/testdata/geo"
+
+// Segmenter returns composite types built from another package's named types, which must all be qualified in the generated code.
+type Segmenter interface {
+	Endpoints() [2]geo.Point
+	Bounds() struct {
+		Min, Max geo.Point
+	}
+	Path() map[string][]*[2]geo.Point
+	Stream() <-chan struct{ From, To geo.Point }
+}
+
+// Tracer is skipped, because the unexported field makes the anonymous struct impossible to declare outside this package.
+type Tracer interface {
+	Trace() struct{ at geo.Point }
+}
@@ -0,0 +1,10 @@
+//go:build unix
+
+// Package tagged only builds on unix systems, so the function types generated into it with --qualify-relative-to inherit its //go:build unix constraint.
+package tagged
+
+type Signal int
+
+type Notifier interface {
+	Notify(sig Signal) error
+}
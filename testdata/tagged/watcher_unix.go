@@ -0,0 +1,7 @@
+//go:build unix
+
+package tagged
+
+type Watcher interface {
+	Watch(path string) (<-chan Signal, error)
+}
@@ -0,0 +1,18 @@
+package unexported
+
+// Session's unexported methods are skipped, fail the generation with --fail-on-unexported-methods, or are converted to the exported function types Touch and Expire with --export-unexported.
+type Session interface {
+	ID() string
+	touch()
+	expire(after int) (expired bool, err error)
+}
+
+// opts is unexported, so Runner's Do can't be converted outside the package and is skipped with a warning.
+type opts struct {
+	retries int
+}
+
+type Runner interface {
+	Do(o opts) error
+	Name() string
+}
@@ -0,0 +1,9 @@
+// Package unsafeptr has methods with unsafe.Pointer parameters and results, which is a basic type declared by the unsafe package.
+package unsafeptr
+
+import "unsafe"
+
+type Buffer interface {
+	Addr() unsafe.Pointer
+	Store(p unsafe.Pointer, n int) error
+}
@@ -0,0 +1,6 @@
+package stale // import "example.com/stale"
+
+// The import comment is stale: the package is resolved by its path in the module.
+type Pinger interface {
+	Ping() error
+}
@@ -0,0 +1,9 @@
+package vanity // import "github.com/eaardal/functypes/testdata/vanity"
+
+type Token struct {
+	Value string
+}
+
+type Issuer interface {
+	Issue(subject string) (Token, error)
+}
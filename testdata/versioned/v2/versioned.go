@@ -0,0 +1,6 @@
+// Package versioned lives in a major version directory, so its output file must be named after the package rather than the directory.
+package versioned
+
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
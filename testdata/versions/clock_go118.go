@@ -0,0 +1,11 @@
+//go:build go1.18
+
+package versions
+
+import "time"
+
+// Clock is the variant current toolchains build.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
@@ -0,0 +1,22 @@
+package widen
+
+type File struct {
+	name string
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+
+type Options struct {
+	Overwrite bool
+}
+
+type Named interface {
+	Name() string
+}
+
+// Uploader's *File parameter is widened to Named with --widen-params, while Options has no matching interface and is kept.
+type Uploader interface {
+	Upload(f *File, opts Options) error
+}